@@ -0,0 +1,195 @@
+package health
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	sauté "gitlab.com/biffen/saute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ Authorizer = AuthorizerFunc(nil)
+	_ Authorizer = BearerToken("")
+)
+
+// Authorizer decides whether a request may use the admin API; see [WithAdmin].
+type Authorizer interface {
+	Authorize(req *http.Request) bool
+}
+
+// AuthorizerFunc is a wrapper for a function that implements [Authorizer].
+type AuthorizerFunc func(*http.Request) bool
+
+// Authorize implements [Authorizer] by calling the [AuthorizerFunc].
+func (f AuthorizerFunc) Authorize(req *http.Request) bool {
+	return f(req)
+}
+
+// BearerToken is an [Authorizer] that requires requests to have an
+// ‘Authorization: Bearer …’ header with the token.
+type BearerToken string
+
+// Authorize implements [Authorizer].
+func (token BearerToken) Authorize(req *http.Request) bool {
+	if token == "" {
+		return false
+	}
+
+	str, ok := strings.CutPrefix(
+		req.Header.Get(headers.Authorization),
+		"Bearer ",
+	)
+
+	return ok && subtle.ConstantTimeCompare([]byte(str), []byte(token)) == 1
+}
+
+// ClientCertificate returns an [Authorizer] that requires requests to be made
+// over TLS with a verified client certificate (i.e. the server must be set up
+// for mutual TLS) for which verify returns true.
+func ClientCertificate(verify func(*x509.Certificate) bool) Authorizer {
+	return AuthorizerFunc(func(req *http.Request) bool {
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 ||
+			len(req.TLS.VerifiedChains[0]) == 0 {
+			return false
+		}
+
+		return verify(req.TLS.VerifiedChains[0][0])
+	})
+}
+
+// WithAdmin is a [HandlerOption] for [NewHandler] to enable the admin API at
+// the path prefix followed by ‘/admin’. Requests must be authorised by (at
+// least) one of the authorizers. The API is:
+//
+//	GET    /admin                    current state
+//	PUT    /admin/status             force the status; see [ForcedStatus]
+//	DELETE /admin/status             stop forcing the status
+//	PUT    /admin/maintenance/{name} set a checker in maintenance; see [Maintenance]
+//	DELETE /admin/maintenance/{name} end a checker’s maintenance
+//
+// Request and response bodies are JSON. Forcing the status requires a ‘status’.
+func WithAdmin(authorizers ...Authorizer) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.admin = true
+		h.authorizers = authorizers
+	})
+}
+
+type adminState struct {
+	Draining    bool                   `json:"draining"`
+	Forced      *ForcedStatus          `json:"forced,omitempty"`
+	Maintenance map[string]Maintenance `json:"maintenance,omitempty"`
+}
+
+func (h *handler) authorized(req *http.Request) bool {
+	for _, authorizer := range h.authorizers {
+		if authorizer.Authorize(req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *handler) serveAdmin(
+	w http.ResponseWriter,
+	req *http.Request,
+	path string,
+) {
+	ctx, span := sauté.TraceFunc(extract(req), nil)
+	defer span.End()
+
+	errorStatus := func(err error, status int) {
+		if err == nil {
+			http.Error(w, "", status)
+			span.SetStatus(codes.Error, http.StatusText(status))
+
+			return
+		}
+
+		http.Error(w, err.Error(), status)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	if !h.authorized(req) {
+		errorStatus(nil, http.StatusUnauthorized)
+		return
+	}
+
+	name, isMaintenance := strings.CutPrefix(path, "/maintenance/")
+
+	switch {
+	case path == "" && req.Method == http.MethodGet:
+
+	case path == "/status" && req.Method == http.MethodPut:
+		var forced struct {
+			ForcedStatus
+
+			// Required, as defaulting to ‘pass’ would hide real failures.
+			Status *Status `json:"status"`
+		}
+
+		if err := json.NewDecoder(req.Body).Decode(&forced); err != nil {
+			errorStatus(err, http.StatusBadRequest)
+			return
+		}
+
+		if forced.Status == nil {
+			errorStatus(errors.New("missing status"), http.StatusBadRequest)
+			return
+		}
+
+		forced.ForcedStatus.Status = *forced.Status
+
+		h.registry.ForceStatus(ctx, forced.ForcedStatus)
+
+	case path == "/status" && req.Method == http.MethodDelete:
+		h.registry.UnforceStatus(ctx)
+
+	case isMaintenance && name != "" && req.Method == http.MethodPut:
+		var maintenance Maintenance
+
+		if err := json.NewDecoder(req.Body).Decode(&maintenance); err != nil {
+			errorStatus(err, http.StatusBadRequest)
+			return
+		}
+
+		if err := h.registry.SetMaintenance(ctx, name, maintenance); err != nil {
+			errorStatus(err, http.StatusNotFound)
+			return
+		}
+
+	case isMaintenance && name != "" && req.Method == http.MethodDelete:
+		h.registry.EndMaintenance(ctx, name)
+
+	case path == "", path == "/status", isMaintenance && name != "":
+		errorStatus(nil, http.StatusMethodNotAllowed)
+		return
+
+	default:
+		errorStatus(nil, http.StatusNotFound)
+		return
+	}
+
+	state := adminState{
+		Draining:    h.registry.Draining(),
+		Maintenance: h.registry.InMaintenance(),
+	}
+
+	if forced, ok := h.registry.Forced(); ok {
+		state.Forced = &forced
+	}
+
+	w.Header().Set(headers.ContentType, "application/json")
+
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		errorStatus(err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,145 @@
+package health_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestWithAdmin(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "db", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusFail}}
+	})
+
+	handler := health.NewHandler(
+		health.WithAdmin(health.BearerToken("s3cr3t")),
+		health.WithRegistry(&registry),
+	)
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set(headers.Authorization, "Bearer "+token)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/admin", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/admin", "nope", "").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/admin", "s3cr3t", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/administrator", "s3cr3t", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "/admin/status", "s3cr3t", "").Code)
+
+	assert.Equal(t, http.StatusInternalServerError, do(http.MethodGet, "/", "", "").Code)
+
+	w := do(http.MethodPut, "/admin/maintenance/db", "s3cr3t",
+		`{"reason":"migrating","until":"2999-01-01T00:00:00Z"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"draining": false,
+		"maintenance": {
+			"db": {"reason": "migrating", "until": "2999-01-01T00:00:00Z"}
+		}
+	}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound,
+		do(http.MethodPut, "/admin/maintenance/nope", "s3cr3t", `{}`).Code)
+
+	w = do(http.MethodGet, "/", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp, err := health.ReadResponse(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+	assert.Equal(t, []string{
+		"db is in maintenance until 2999-01-01T00:00:00Z: migrating",
+	}, resp.Notes)
+
+	assert.Equal(t, http.StatusBadRequest,
+		do(http.MethodPut, "/admin/status", "s3cr3t", `{"reason":"db migration"}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/readyz", "", "").Code)
+
+	require.Equal(t, http.StatusOK,
+		do(http.MethodPut, "/admin/status", "s3cr3t", `{"status":"fail"}`).Code)
+
+	assert.Equal(t, http.StatusInternalServerError, do(http.MethodGet, "/readyz", "", "").Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/livez", "", "").Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, "/admin/status", "s3cr3t", "").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, "/admin/maintenance/db", "s3cr3t", "").Code)
+
+	assert.Equal(t, http.StatusInternalServerError, do(http.MethodGet, "/", "", "").Code)
+}
+
+func TestRegistry_ForceStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.ForceStatus(ctx, health.ForcedStatus{
+		Status: health.StatusFail,
+		Reason: "expired",
+		Until:  time.Now().Add(-time.Second),
+	})
+
+	_, ok := registry.Forced()
+	assert.False(t, ok)
+
+	registry.ForceStatus(ctx, health.ForcedStatus{
+		Status: health.StatusFail,
+		Reason: "migration",
+	})
+
+	resp, err := registry.CheckProbe(ctx, health.ProbeReadiness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusFail, resp.Status)
+	assert.Equal(t, []string{"status forced to fail: migration"}, resp.Notes)
+
+	resp, err = registry.CheckProbe(ctx, health.ProbeLiveness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusPass, resp.Status)
+}
+
+func TestClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cert := &x509.Certificate{}
+	authorizer := health.ClientCertificate(func(c *x509.Certificate) bool {
+		return c == cert
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	assert.False(t, authorizer.Authorize(req))
+
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{cert}},
+	}
+	assert.True(t, authorizer.Authorize(req))
+}
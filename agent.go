@@ -0,0 +1,229 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	sauté "gitlab.com/biffen/saute"
+)
+
+const (
+	agentTimeout   = 10 * time.Second
+	maxAcceptDelay = time.Second
+	minAcceptDelay = 5 * time.Millisecond
+)
+
+var _ AgentOption = agentOptionFunc(nil)
+
+// AgentOption is an optional configuration for [ServeAgent] and
+// [StartAgentServer].
+type AgentOption interface {
+	applyAgent(*agentConfig) error
+}
+
+// ServeAgent answers HAProxy agent checks (see ‘agent-check’ in the HAProxy
+// documentation) on a listener, until the passed [context.Context] is
+// cancelled. The answer is derived from [ProbeReadiness]:
+//
+//   - ‘drain’ while draining (see [SetDraining])
+//   - ‘maint’ while the status is forced to ‘fail’ (see [Registry.ForceStatus])
+//   - ‘ready up 100%’ for ‘pass’
+//   - ‘ready up 50%’ for ‘warn’ (see [WithWarnWeight])
+//   - ‘down’ for ‘fail’
+//
+// ‘ready’ takes the server out of the drain or maintenance mode set by an
+// earlier answer; ‘up’ alone wouldn’t.
+//
+// The listener is closed when ServeAgent returns.
+func ServeAgent(
+	ctx context.Context,
+	listener net.Listener,
+	options ...AgentOption,
+) error {
+	c, err := newAgentConfig(options)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	return c.serve(ctx, listener)
+}
+
+// StartAgentServer starts a TCP server at addr answering HAProxy agent checks;
+// see [ServeAgent].
+//
+// Will block until the server is listening.
+//
+// The server will be stopped when the passed [context.Context] is cancelled.
+func StartAgentServer(
+	ctx context.Context,
+	addr string,
+	options ...AgentOption,
+) error {
+	c, err := newAgentConfig(options)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := c.serve(ctx, listener); err != nil {
+			slog.ErrorContext(ctx, "agent check server error",
+				logSubsystem,
+				slog.Any("error", err),
+			)
+		}
+	}()
+
+	return nil
+}
+
+// WithAgentRegistry is an [AgentOption] to specify the [Registry] whose checks
+// to answer with.
+func WithAgentRegistry(registry *Registry) AgentOption {
+	return agentOptionFunc(func(c *agentConfig) error {
+		c.Registry = registry
+		return nil
+	})
+}
+
+// WithWarnWeight is an [AgentOption] to specify the weight, in percent, to
+// answer with while the status is ‘warn’. The default is 50.
+func WithWarnWeight(percent uint) AgentOption {
+	return agentOptionFunc(func(c *agentConfig) error {
+		if percent > 100 {
+			return fmt.Errorf("weight %d%% out of range", percent)
+		}
+
+		c.WarnWeight = percent
+
+		return nil
+	})
+}
+
+type agentConfig struct {
+	Registry   *Registry
+	WarnWeight uint
+}
+
+func newAgentConfig(options []AgentOption) (*agentConfig, error) {
+	c := &agentConfig{
+		Registry:   &defaultRegistry,
+		WarnWeight: 50,
+	}
+
+	for _, option := range options {
+		if err := option.applyAgent(c); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *agentConfig) answer(ctx context.Context, conn net.Conn) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(agentTimeout))
+
+	ctx, cancel := context.WithTimeout(ctx, agentTimeout)
+	defer cancel()
+
+	if _, err := conn.Write([]byte(c.status(ctx) + "\n")); err != nil {
+		slog.DebugContext(ctx, "failed to answer agent check",
+			logSubsystem,
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (c *agentConfig) serve(ctx context.Context, listener net.Listener) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = listener.Close()
+	})
+	defer stop()
+
+	var delay time.Duration
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+
+			// Back off, like net/http.Server, e.g. when out of file
+			// descriptors.
+			delay = min(max(2*delay, minAcceptDelay), maxAcceptDelay)
+
+			slog.ErrorContext(ctx, "failed to accept agent check connection",
+				logSubsystem,
+				slog.Any("error", err),
+				slog.Duration("delay", delay),
+			)
+
+			timer := time.NewTimer(delay)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+
+			case <-timer.C:
+			}
+
+			continue
+		}
+
+		delay = 0
+
+		go c.answer(ctx, conn)
+	}
+}
+
+func (c *agentConfig) status(ctx context.Context) string {
+	if c.Registry.Draining() {
+		return "drain"
+	}
+
+	if forced, ok := c.Registry.Forced(); ok && forced.Status == StatusFail {
+		return "maint"
+	}
+
+	resp, err := c.Registry.CheckProbe(ctx, ProbeReadiness)
+	if err != nil {
+		return "down"
+	}
+
+	switch resp.Status {
+	case StatusPass:
+		return "ready up 100%"
+
+	case StatusWarn:
+		return fmt.Sprintf("ready up %d%%", c.WarnWeight)
+
+	default:
+		return "down"
+	}
+}
+
+type agentOptionFunc func(*agentConfig) error
+
+func (f agentOptionFunc) applyAgent(c *agentConfig) error {
+	return f(c)
+}
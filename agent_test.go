@@ -0,0 +1,146 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestServeAgent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		registry health.Registry
+		status   atomic.Uint32
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.Status(status.Load())}}
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = health.ServeAgent(ctx, listener,
+			health.WithAgentRegistry(&registry),
+			health.WithWarnWeight(25),
+		)
+	}()
+
+	ask := func() string {
+		t.Helper()
+
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		answer, err := io.ReadAll(conn)
+		require.NoError(t, err)
+
+		return string(answer)
+	}
+
+	assert.Equal(t, "ready up 100%\n", ask())
+
+	status.Store(uint32(health.StatusWarn))
+	assert.Equal(t, "ready up 25%\n", ask())
+
+	status.Store(uint32(health.StatusFail))
+	assert.Equal(t, "down\n", ask())
+
+	registry.ForceStatus(ctx, health.ForcedStatus{Status: health.StatusFail})
+	assert.Equal(t, "maint\n", ask())
+
+	registry.SetDraining(ctx, true)
+	assert.Equal(t, "drain\n", ask())
+
+	registry.SetDraining(ctx, false)
+	registry.UnforceStatus(ctx)
+	status.Store(uint32(health.StatusPass))
+	assert.Equal(t, "ready up 100%\n", ask())
+}
+
+func TestStartAgentServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	// Find a free port.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	require.NoError(t, health.StartAgentServer(ctx, addr,
+		health.WithAgentRegistry(&health.Registry{}),
+	))
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	answer, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "ready up 100%\n", string(answer))
+
+	assert.Error(t, health.StartAgentServer(ctx, "127.0.0.1:0",
+		health.WithWarnWeight(101),
+	))
+}
+
+func TestServeAgent_acceptError(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	listener := &failingListener{closed: make(chan struct{})}
+
+	require.NoError(t, health.ServeAgent(ctx, listener))
+
+	// 5 + 10 + 20 + 40 ms, and then waiting for 80 ms.
+	assert.LessOrEqual(t, listener.accepts.Load(), int32(6), "backs off")
+}
+
+// failingListener fails to accept connections until closed.
+type failingListener struct {
+	accepts atomic.Int32
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func (l *failingListener) Accept() (net.Conn, error) {
+	l.accepts.Add(1)
+
+	select {
+	case <-l.closed:
+		return nil, net.ErrClosed
+
+	default:
+		return nil, errors.New("too many open files")
+	}
+}
+
+func (l *failingListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+func (l *failingListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
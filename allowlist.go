@@ -0,0 +1,123 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+)
+
+// WithAllowlist is a [HandlerOption] for [NewHandler] to only allow clients
+// with addresses within the prefixes access to a [Detail] level. It can be
+// used once per level, e.g.:
+//
+//	health.WithAllowlist(health.DetailFull, netip.MustParsePrefix("10.0.0.0/8")),
+//	health.WithAllowlist(health.DetailStatus, netip.MustParsePrefix("0.0.0.0/0")),
+//
+// Clients get the most detailed level they are allowed (but never more than
+// [WithDetail]). As soon as any allowlist is set up, clients not in any of them
+// are denied access altogether. Clients connecting over Unix domain sockets are
+// always allowed.
+//
+// See [WithTrustedProxies] for handling of ‘X-Forwarded-For’.
+func WithAllowlist(detail Detail, prefixes ...netip.Prefix) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		if h.allowlists == nil {
+			h.allowlists = make(map[Detail][]netip.Prefix)
+		}
+
+		for _, prefix := range prefixes {
+			h.allowlists[detail] = append(h.allowlists[detail], prefix.Masked())
+		}
+	})
+}
+
+// WithTrustedProxies is a [HandlerOption] for [NewHandler] to specify proxies
+// whose ‘X-Forwarded-For’ headers are trusted when determining the client’s
+// address for [WithAllowlist].
+func WithTrustedProxies(prefixes ...netip.Prefix) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		for _, prefix := range prefixes {
+			h.trustedProxies = append(h.trustedProxies, prefix.Masked())
+		}
+	})
+}
+
+// access returns the level of detail a request is allowed, or false if it is
+// denied.
+func (h *handler) access(req *http.Request) (Detail, bool) {
+	if h.allowlists == nil {
+		return h.detail, true
+	}
+
+	if local, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok &&
+		local.Network() == "unix" {
+		return h.detail, true
+	}
+
+	addr, ok := h.clientAddr(req)
+	if !ok {
+		return 0, false
+	}
+
+	var (
+		allowed bool
+		best    Detail
+	)
+
+	for detail, prefixes := range h.allowlists {
+		if contains(prefixes, addr) && (!allowed || detail < best) {
+			allowed, best = true, detail
+		}
+	}
+
+	return max(best, h.detail), allowed
+}
+
+// clientAddr returns the client’s address, following ‘X-Forwarded-For’ headers
+// as long as they are from trusted proxies.
+func (h *handler) clientAddr(req *http.Request) (netip.Addr, bool) {
+	addrPort, err := netip.ParseAddrPort(req.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	addr := addrPort.Addr().Unmap()
+
+	if !contains(h.trustedProxies, addr) {
+		return addr, true
+	}
+
+	var forwarded []string
+
+	for _, value := range req.Header.Values(headers.XForwardedFor) {
+		for str := range strings.SplitSeq(value, ",") {
+			forwarded = append(forwarded, strings.TrimSpace(str))
+		}
+	}
+
+	// The rightmost address is the one added by the closest proxy.
+	for _, str := range slices.Backward(forwarded) {
+		next, err := netip.ParseAddr(str)
+		if err != nil {
+			break
+		}
+
+		addr = next.Unmap()
+
+		if !contains(h.trustedProxies, addr) {
+			break
+		}
+	}
+
+	return addr, true
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	return slices.ContainsFunc(prefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
@@ -0,0 +1,84 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestWithAllowlist(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "db", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusPass}}
+	})
+
+	handler := health.NewHandler(
+		health.WithAllowlist(health.DetailFull,
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("::1/128"),
+		),
+		health.WithAllowlist(health.DetailStatus,
+			netip.MustParsePrefix("192.0.2.0/24"),
+			netip.MustParsePrefix("10.0.0.0/8"),
+		),
+		health.WithRegistry(&registry),
+		health.WithTrustedProxies(netip.MustParsePrefix("172.16.0.0/12")),
+	)
+
+	for _, test := range [...]struct {
+		name, remoteAddr string
+		forwardedFor     []string
+		code             int
+		full             bool
+	}{
+		{"full", "10.1.2.3:1234", nil, http.StatusOK, true},
+		{"IPv6", "[::1]:1234", nil, http.StatusOK, true},
+		{"mapped", "[::ffff:10.1.2.3]:1234", nil, http.StatusOK, true},
+		{"status", "192.0.2.1:1234", nil, http.StatusOK, false},
+		{"denied", "198.51.100.1:1234", nil, http.StatusForbidden, false},
+		{"not a proxy", "192.0.2.1:1234", []string{"10.1.2.3"}, http.StatusOK, false},
+		{"proxy", "172.16.0.1:1234", []string{"10.1.2.3"}, http.StatusOK, true},
+		{"proxies", "172.16.0.1:1234", []string{"198.51.100.1, 10.1.2.3", "172.16.0.2"}, http.StatusOK, true},
+		{"spoofed", "172.16.0.1:1234", []string{"10.1.2.3, 198.51.100.1"}, http.StatusForbidden, false},
+		{"proxy only", "172.16.0.1:1234", nil, http.StatusForbidden, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+
+			for _, value := range test.forwardedFor {
+				req.Header.Add(headers.XForwardedFor, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			require.Equal(t, test.code, w.Code)
+
+			if test.code != http.StatusOK {
+				return
+			}
+
+			resp, err := health.ReadResponse(w.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.full, resp.Checks != nil)
+		})
+	}
+}
@@ -1,68 +1,56 @@
 package health
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-http-utils/headers"
 	sauté "gitlab.com/biffen/saute"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
-var _ Option = optionFunc(nil)
+var (
+	_ CheckManyOption = CheckOptions(nil)
+	_ MainOption      = CheckOptions(nil)
+	_ MainOption      = mainOptionFunc(nil)
+	_ Option          = optionFunc(nil)
+	_ WatchOption     = CheckOptions(nil)
+)
 
 // CheckHealth gets a Response from an HTTP server.
+//
+// Errors can be matched with [errors.Is] and [ErrUnreachable] or [errors.As]
+// and [*ContentTypeError], [*DecodeError] or [*StatusError]. A Response is
+// returned without an error even if the HTTP status code indicates a failure,
+// e.g. 500, as long as the body is a valid health response.
 func CheckHealth(ctx context.Context, options ...Option) (*Response, error) {
-	ctx, span := sauté.TraceFunc(ctx, nil)
-	defer span.End()
-
-	c := config{
-		Host:    "127.0.0.1",
-		Port:    port(),
-		Timeout: timeout,
-	}
-
-	for _, option := range options {
-		if err := option.apply(&c); err != nil {
-			return nil, fmt.Errorf("invalid option: %w", err)
-		}
-	}
-
-	var (
-		addr = fmt.Sprintf(
-			"http://%s/",
-			net.JoinHostPort(c.Host, strconv.FormatUint(uint64(c.Port), 10)),
-		)
-		client = http.Client{
-			Timeout: c.Timeout,
-		}
-	)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	c, err := newConfig(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Add(headers.Accept, ContentType)
-
-	httpResp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
-	}
-
-	defer httpResp.Body.Close()
-
-	resp, err := ReadResponse(httpResp.Body)
+	client, closeIdle, err := c.client()
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	defer closeIdle()
+
+	return c.checkHealth(ctx, client)
 }
 
 const (
@@ -71,48 +59,254 @@ const (
 	// ExitUser is the exit code when the user did something wrong.
 	ExitUser = 2
 
-	timeout = 30 * time.Second
+	maxResponseSize = 1 << 20
+	timeout         = 30 * time.Second
 )
 
 // Main is a utility for services that exits the current process with 0 or 1 for
-// a healthy or unhealthy state, respectively.
-func Main(ctx context.Context) {
-	var exit int
-	defer func() {
-		//nolint:revive // Exiting here is intentional.
-		os.Exit(exit)
-	}()
+// a healthy or unhealthy state, respectively. See [MainCode].
+func Main(ctx context.Context, options ...MainOption) {
+	//nolint:revive // Exiting here is intentional.
+	os.Exit(MainCode(ctx, options...))
+}
 
+// MainCode is like [Main] but returns the exit code instead of exiting: 0 for a
+// healthy state, otherwise [ExitErr], or [ExitUser] for invalid options.
+//
+// The options from [WithCheckOptions] are passed on to [CheckHealth]. The
+// output to stdout can be changed with [WithOutputMode] and ‘warn’ can be
+// treated as unhealthy with [WithFailOnWarn].
+func MainCode(ctx context.Context, options ...MainOption) int {
 	ctx, span := sauté.TraceFunc(ctx, nil)
 	defer span.End()
 
-	resp, err := CheckHealth(ctx)
+	var c mainConfig
+
+	for _, option := range options {
+		if err := option.applyMain(&c); err != nil {
+			slog.ErrorContext(ctx, "invalid option",
+				slog.Any("error", err),
+			)
+
+			return ExitUser
+		}
+	}
+
+	if err := ValidateOptions(c.Options...); err != nil {
+		slog.ErrorContext(ctx, "invalid option",
+			slog.Any("error", err),
+		)
+
+		return ExitUser
+	}
+
+	resp, err := CheckHealth(ctx, c.Options...)
 	if err != nil {
 		slog.ErrorContext(ctx, "error",
 			slog.Any("error", err),
 		)
 
-		exit = ExitErr
-
-		return
+		return ExitErr
 	}
 
-	_, _ = resp.Write(os.Stdout)
+	switch c.OutputMode {
+	case OutputModeJSON:
+		_, _ = resp.Write(os.Stdout)
 
-	if !resp.Good() {
-		exit = ExitErr
+	case OutputModeShort:
+		_, _ = fmt.Fprintln(os.Stdout, resp.Status)
+
+	case OutputModeSilent:
+	}
+
+	if !resp.Good() || (c.FailOnWarn && resp.Status == StatusWarn) {
+		return ExitErr
 	}
+
+	return 0
 }
 
+// MainOption is an optional configuration for [Main] and [MainCode].
+type MainOption interface {
+	applyMain(*mainConfig) error
+}
+
+// WithFailOnWarn is a [MainOption] for [Main] and [MainCode] to treat the
+// status ‘warn’ as unhealthy.
+func WithFailOnWarn() MainOption {
+	return mainOptionFunc(func(c *mainConfig) error {
+		c.FailOnWarn = true
+		return nil
+	})
+}
+
+// WithOutputMode is a [MainOption] for [Main] and [MainCode] to specify what
+// to write to stdout.
+func WithOutputMode(mode OutputMode) MainOption {
+	return mainOptionFunc(func(c *mainConfig) error {
+		if mode < OutputModeJSON || mode > OutputModeSilent {
+			return fmt.Errorf("invalid output mode %d", mode)
+		}
+
+		c.OutputMode = mode
+
+		return nil
+	})
+}
+
+// OutputMode is what [Main] and [MainCode] write to stdout.
+type OutputMode int
+
+const (
+	// OutputModeJSON writes the complete Response as JSON. This is the
+	// default.
+	OutputModeJSON OutputMode = iota
+	// OutputModeShort writes only the status, e.g. ‘pass’.
+	OutputModeShort
+	// OutputModeSilent writes nothing.
+	OutputModeSilent
+)
+
 // Option is an optional configuration for [CheckHealth].
 type Option interface {
 	apply(*config) error
 }
 
-// WithHost is an [Option] for [CheckHealth] to specify the host.
+// CheckOptions are [Option]s for the checks made by [CheckMany], [Main],
+// [MainCode] and [Watch]; see [WithCheckOptions].
+type CheckOptions []Option
+
+// WithCheckOptions returns [CheckOptions] to specify [Option]s, e.g.
+// [WithTimeout], for every check made by [CheckMany], [Main], [MainCode] or
+// [Watch].
+func WithCheckOptions(options ...Option) CheckOptions {
+	return options
+}
+
+func (o CheckOptions) applyCheckMany(c *checkManyConfig) error {
+	c.Options = append(c.Options, o...)
+	return nil
+}
+
+func (o CheckOptions) applyMain(c *mainConfig) error {
+	c.Options = append(c.Options, o...)
+	return nil
+}
+
+func (o CheckOptions) applyWatch(c *watchConfig) error {
+	c.Options = append(c.Options, o...)
+	return nil
+}
+
+// ValidateOptions returns an error if any of the options is invalid, i.e. the
+// same error [CheckHealth] would return before making any request. E.g. a
+// command line tool can use it to tell bad arguments from failed checks.
+func ValidateOptions(options ...Option) error {
+	c, err := newConfig(options)
+	if err != nil {
+		return err
+	}
+
+	_, closeIdle, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	closeIdle()
+
+	return nil
+}
+
+// WithBasicAuth is an [Option] for [CheckHealth] to authenticate with HTTP
+// basic authentication.
+func WithBasicAuth(username, password string) Option {
+	return optionFunc(func(c *config) error {
+		c.Authorization = "Basic " + base64.StdEncoding.EncodeToString(
+			[]byte(username+":"+password),
+		)
+
+		return nil
+	})
+}
+
+// WithBearerToken is an [Option] for [CheckHealth] to authenticate with a
+// bearer token.
+func WithBearerToken(token string) Option {
+	return optionFunc(func(c *config) error {
+		c.Authorization = "Bearer " + token
+		return nil
+	})
+}
+
+// WithHeader is an [Option] for [CheckHealth] to add a header to the request.
+func WithHeader(key, value string) Option {
+	return optionFunc(func(c *config) error {
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+
+		c.Header.Add(key, value)
+
+		return nil
+	})
+}
+
+// WithHost is an [Option] for [CheckHealth] to specify the host. The host can
+// include a port number, e.g. ‘example.com:9999’ or ‘[::1]:9999’, or be a
+// complete URL; see [WithURL].
+//
+// By default the Unix domain socket in ${HEALTH_SOCKET} is used, if set, and
+// otherwise 127.0.0.1.
 func WithHost(host string) Option {
 	return optionFunc(func(c *config) error {
-		c.Host = host
+		if strings.Contains(host, "://") {
+			return WithURL(host).apply(c)
+		}
+
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			port, err := strconv.ParseUint(p, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid port in %q: %w", host, err)
+			}
+
+			c.Host, c.Port = h, uint16(port)
+		} else {
+			c.Host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		}
+
+		c.Socket = ""
+
+		return nil
+	})
+}
+
+// WithHTTPClient is an [Option] for [CheckHealth] to use a custom
+// [net/http.Client]. Its timeout is used unless it is 0. Other options that
+// affect the transport (e.g. [WithTLSConfig]) require its transport to be nil
+// or an [*net/http.Transport].
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(c *config) error {
+		if client == nil {
+			return errors.New("nil HTTP client")
+		}
+
+		c.HTTPClient = client
+
+		return nil
+	})
+}
+
+// WithMaxResponseSize is an [Option] for [CheckHealth] to specify the maximum
+// size, in bytes, of the response body. Larger responses result in a
+// [*DecodeError] wrapping a [*net/http.MaxBytesError]. The default is 1 MiB.
+func WithMaxResponseSize(size int64) Option {
+	return optionFunc(func(c *config) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid maximum response size %d", size)
+		}
+
+		c.MaxResponseSize = size
+
 		return nil
 	})
 }
@@ -125,7 +319,17 @@ func WithPort(port uint16) Option {
 	})
 }
 
-// WithTimeout is an [Option] for [CheckHealth] to specify a timeout. See
+// WithStrict is an [Option] for [CheckHealth] to reject responses with
+// unknown fields.
+func WithStrict() Option {
+	return optionFunc(func(c *config) error {
+		c.Strict = true
+		return nil
+	})
+}
+
+// WithTimeout is an [Option] for [CheckHealth] to specify a timeout for the
+// whole check, including any retries (see [WithRetries]). See also
 // [net/http.Client.Timeout].
 func WithTimeout(timeout time.Duration) Option {
 	return optionFunc(func(c *config) error {
@@ -134,10 +338,292 @@ func WithTimeout(timeout time.Duration) Option {
 	})
 }
 
+// WithTLSConfig is an [Option] for [CheckHealth] to specify the TLS
+// configuration, e.g. a custom CA (RootCAs), client certificates
+// (Certificates) or the server name (ServerName).
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return optionFunc(func(c *config) error {
+		c.TLSConfig = tlsConfig
+		return nil
+	})
+}
+
+// WithTransport is an [Option] for [CheckHealth] to use a custom
+// [net/http.RoundTripper]. Other options that affect the transport (e.g.
+// [WithTLSConfig]) require it to be an [*net/http.Transport].
+func WithTransport(transport http.RoundTripper) Option {
+	return optionFunc(func(c *config) error {
+		c.Transport = transport
+		return nil
+	})
+}
+
+// WithURL is an [Option] for [CheckHealth] to specify the complete URL of the
+// health endpoint, e.g. ‘https://example.com/internal/health’. The scheme can
+// be ‘http’, ‘https’ or ‘unix’, the latter followed by the path to a Unix
+// domain socket, e.g. ‘unix:///run/app/health.sock’.
+func WithURL(rawURL string) Option {
+	return optionFunc(func(c *config) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+
+		switch u.Scheme {
+		case "unix":
+			path := u.Host + u.Path
+			if path == "" {
+				return fmt.Errorf("empty socket path in %q", rawURL)
+			}
+
+			c.Socket = path
+			c.Scheme, c.Host, c.Path, c.RawQuery = "http", "localhost", "/", ""
+
+			return nil
+
+		case "http", "https":
+
+		default:
+			return fmt.Errorf("unsupported scheme in %q", rawURL)
+		}
+
+		if u.Hostname() == "" {
+			return fmt.Errorf("no host in %q", rawURL)
+		}
+
+		c.Port = defaultPorts[u.Scheme]
+
+		if p := u.Port(); p != "" {
+			port, err := strconv.ParseUint(p, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid port in %q: %w", rawURL, err)
+			}
+
+			c.Port = uint16(port)
+		}
+
+		c.Host = u.Hostname()
+		c.Path = cmp.Or(u.Path, "/")
+		c.RawQuery = u.RawQuery
+		c.Scheme = u.Scheme
+		c.Socket = ""
+
+		return nil
+	})
+}
+
+var defaultPorts = map[string]uint16{
+	"http":  80,
+	"https": 443,
+}
+
+// check makes a single attempt at getting a Response.
+func (c *config) check(
+	ctx context.Context,
+	client *http.Client,
+) (*Response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.url().String(),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	maps.Copy(req.Header, c.Header.Clone())
+	req.Header.Set(headers.Accept, ContentType)
+
+	if c.Authorization != "" {
+		req.Header.Set(headers.Authorization, c.Authorization)
+	}
+
+	// Propagate the trace context and baggage, so that the server’s spans are
+	// part of the same trace.
+	otel.GetTextMapPropagator().Inject(
+		ctx,
+		propagation.HeaderCarrier(req.Header),
+	)
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, unreachable(err)
+	}
+
+	defer httpResp.Body.Close()
+
+	return c.readResponse(httpResp)
+}
+
 type config struct {
-	Host    string
-	Port    uint16
-	Timeout time.Duration
+	Authorization   string
+	Backoff         time.Duration
+	Header          http.Header
+	Host            string
+	HTTPClient      *http.Client
+	MaxBackoff      time.Duration
+	MaxResponseSize int64
+	Path            string
+	Port            uint16
+	RawQuery        string
+	Retries         uint
+	RetryIf         func(error) bool
+	Scheme          string
+	Socket          string
+	Strict          bool
+	Timeout         time.Duration
+	TLSConfig       *tls.Config
+	Transport       http.RoundTripper
+}
+
+// newConfig returns the default configuration with the options applied.
+func newConfig(options []Option) (*config, error) {
+	c := &config{
+		Backoff:         defaultBackoff,
+		Host:            "127.0.0.1",
+		MaxBackoff:      defaultMaxBackoff,
+		MaxResponseSize: maxResponseSize,
+		Path:            "/",
+		Port:            port(),
+		RetryIf:         Retryable,
+		Scheme:          "http",
+		Socket:          os.Getenv(EnvHealthSocket),
+		Timeout:         timeout,
+	}
+
+	for _, option := range options {
+		if err := option.apply(c); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// checkHealth gets a Response using a client from [config.client], retrying
+// if configured to.
+func (c *config) checkHealth(
+	ctx context.Context,
+	client *http.Client,
+) (*Response, error) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	for attempt := uint(0); ; attempt++ {
+		resp, err := c.check(ctx, client)
+		if err == nil || attempt >= c.Retries || !c.RetryIf(err) {
+			return resp, err
+		}
+
+		if !c.wait(ctx, attempt) {
+			return nil, err
+		}
+
+		slog.DebugContext(ctx, "retrying health check",
+			logSubsystem,
+			slog.Any("error", err),
+			slog.Uint64("attempt", uint64(attempt)+1),
+		)
+	}
+}
+
+// client returns the HTTP client to use, and a function to close any idle
+// connections of a transport configured just for it.
+func (c *config) client() (*http.Client, func(), error) {
+	var client http.Client
+
+	if c.HTTPClient != nil {
+		client = *c.HTTPClient
+	}
+
+	if client.Timeout == 0 {
+		client.Timeout = c.Timeout
+	}
+
+	if c.Transport != nil {
+		client.Transport = c.Transport
+	}
+
+	if c.Socket == "" && c.TLSConfig == nil {
+		return &client, func() {}, nil
+	}
+
+	var transport *http.Transport
+
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+
+	case *http.Transport:
+		transport = t.Clone()
+
+	default:
+		return nil, nil, fmt.Errorf(
+			"invalid option: can’t configure transport of type %T",
+			t,
+		)
+	}
+
+	if c.Socket != "" {
+		transport.DialContext = func(
+			ctx context.Context,
+			_, _ string,
+		) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", c.Socket)
+		}
+	}
+
+	if c.TLSConfig != nil {
+		transport.TLSClientConfig = c.TLSConfig
+	}
+
+	client.Transport = transport
+
+	return &client, transport.CloseIdleConnections, nil
+}
+
+func (c *config) url() *url.URL {
+	u := &url.URL{
+		Scheme: c.Scheme,
+		Host: net.JoinHostPort(
+			c.Host,
+			strconv.FormatUint(uint64(c.Port), 10),
+		),
+		Path:     c.Path,
+		RawQuery: c.RawQuery,
+	}
+
+	// Leave out the scheme’s default port, as e.g. browsers do.
+	if port, ok := defaultPorts[c.Scheme]; ok && c.Port == port {
+		u.Host = strings.TrimSuffix(u.Host, ":"+u.Port())
+	}
+
+	if c.Socket != "" {
+		u.Scheme, u.Host = "http", "localhost"
+	}
+
+	return u
+}
+
+type mainConfig struct {
+	FailOnWarn bool
+	Options    []Option
+	OutputMode OutputMode
+}
+
+type mainOptionFunc func(*mainConfig) error
+
+func (f mainOptionFunc) applyMain(c *mainConfig) error {
+	return f(c)
 }
 
 type optionFunc func(*config) error
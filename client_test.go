@@ -2,9 +2,27 @@ package health_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/dotse/go-health"
 )
 
@@ -57,3 +75,478 @@ err: %v
 	// resp.Checks["example"][0].Output: "all good"
 	// err: <nil>
 }
+
+func TestWithURL(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusWarn}}
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/internal/health/", health.NewHandler(
+		health.WithPrefix("/internal/health"),
+		health.WithRegistry(&registry),
+	))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	hostPort := strings.TrimPrefix(server.URL, "http://")
+
+	for _, options := range [][]health.Option{
+		{health.WithURL(server.URL + "/internal/health/readyz")},
+		{health.WithHost(server.URL + "/internal/health")},
+		{health.WithStrict(), health.WithURL(server.URL + "/internal/health")},
+	} {
+		resp, err := health.CheckHealth(ctx, options...)
+		require.NoError(t, err)
+		assert.Equal(t, health.StatusWarn, resp.Status)
+	}
+
+	_, err := health.CheckHealth(ctx, health.WithHost(hostPort))
+
+	var statusErr *health.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+
+	for url, expected := range map[string]string{
+		"http://example.com:80/x":   "http://example.com/x",
+		"http://example.com:443/x":  "http://example.com:443/x",
+		"https://example.com/x?y":   "https://example.com/x?y",
+		"https://example.com:443/x": "https://example.com/x",
+		"https://[::1]/x":           "https://[::1]/x",
+		"https://[::1]:8443/x":      "https://[::1]:8443/x",
+	} {
+		var requested string
+
+		_, _ = health.CheckHealth(ctx,
+			health.WithURL(url),
+			health.WithTransport(roundTripFunc(
+				func(req *http.Request) (*http.Response, error) {
+					requested = req.URL.String()
+					return nil, errors.New("not sent")
+				},
+			)),
+		)
+
+		assert.Equal(t, expected, requested, url)
+	}
+
+	for _, url := range []string{
+		"ftp://example.com/",
+		"http:///nohost",
+		"http://example.com:99999/",
+		"unix://",
+	} {
+		_, err := health.CheckHealth(ctx, health.WithURL(url))
+		assert.ErrorContains(t, err, "invalid option", url)
+
+		err = health.ValidateOptions(health.WithURL(url))
+		assert.ErrorContains(t, err, "invalid option", url)
+	}
+
+	assert.NoError(t, health.ValidateOptions(health.WithURL(server.URL)))
+}
+
+func TestCheckHealth_transport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		admin    atomic.Pointer[httptest.ResponseRecorder]
+		ctx      = t.Context()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusPass}}
+	})
+
+	handler := health.NewHandler(
+		health.WithAdmin(health.BearerToken("s3cr3t")),
+		health.WithRegistry(&registry),
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-Test") != "yes" {
+				http.Error(w, "", http.StatusBadRequest)
+				return
+			}
+
+			if username, password, ok := req.BasicAuth(); ok &&
+				(username != "user" || password != "pass") {
+				http.Error(w, "", http.StatusUnauthorized)
+				return
+			}
+
+			if req.URL.Path != "/admin" {
+				handler.ServeHTTP(w, req)
+				return
+			}
+
+			// Keep the admin API’s response for inspection.
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			admin.Store(recorder)
+
+			maps.Copy(w.Header(), recorder.Header())
+			w.WriteHeader(recorder.Code)
+			_, _ = w.Write(recorder.Body.Bytes())
+		},
+	))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	for name, options := range map[string][]health.Option{
+		"TLS config": {
+			health.WithTLSConfig(&tls.Config{RootCAs: roots}),
+		},
+		"HTTP client": {
+			health.WithHTTPClient(server.Client()),
+		},
+		"transport": {
+			health.WithTransport(server.Client().Transport),
+		},
+		"basic auth": {
+			health.WithBasicAuth("user", "pass"),
+			health.WithTLSConfig(&tls.Config{RootCAs: roots}),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := health.CheckHealth(ctx, append(
+				options,
+				health.WithHeader("X-Test", "yes"),
+				health.WithURL(server.URL),
+			)...)
+			require.NoError(t, err)
+			assert.Equal(t, health.StatusPass, resp.Status)
+		})
+	}
+
+	_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+	assert.ErrorContains(t, err, "certificate signed by unknown authority")
+	assert.NotErrorIs(t, err, health.ErrUnreachable)
+
+	// The admin API’s response isn’t a health response, so the result
+	// doesn’t matter, only that the bearer token was accepted.
+	_, _ = health.CheckHealth(ctx,
+		health.WithBearerToken("s3cr3t"),
+		health.WithHeader("X-Test", "yes"),
+		health.WithTLSConfig(&tls.Config{RootCAs: roots}),
+		health.WithURL(server.URL+"/admin"),
+	)
+
+	recorder := admin.Load()
+	require.NotNil(t, recorder)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"draining":false}`, recorder.Body.String())
+}
+
+func TestCheckHealth_errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	serve := func(
+		statusCode int,
+		contentType, body string,
+	) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(statusCode)
+				_, _ = w.Write([]byte(body))
+			},
+		))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		_, err = health.CheckHealth(ctx, health.WithHost(addr))
+		assert.ErrorIs(t, err, health.ErrUnreachable)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusBadGateway,
+			"text/html",
+			"<h1>Bad Gateway</h1>",
+		)
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var statusErr *health.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+		assert.Equal(t, "<h1>Bad Gateway</h1>", statusErr.Body)
+		assert.NotErrorIs(t, err, health.ErrUnreachable)
+	})
+
+	t.Run("status JSON", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusBadGateway,
+			"application/json",
+			`{"message":"Bad Gateway`+"\xff"+`"}`,
+		)
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var statusErr *health.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+		assert.Equal(t, `{"message":"Bad Gateway"}`, statusErr.Body)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(http.StatusOK, "text/html", "<h1>OK</h1>")
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var contentTypeErr *health.ContentTypeError
+		require.ErrorAs(t, err, &contentTypeErr)
+		assert.Equal(t, "text/html", contentTypeErr.ContentType)
+	})
+
+	t.Run("charset", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusOK,
+			health.ContentType+"; charset=iso-8859-1",
+			`{"status":"pass"}`,
+		)
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var contentTypeErr *health.ContentTypeError
+		assert.ErrorAs(t, err, &contentTypeErr)
+	})
+
+	t.Run("size", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusOK,
+			health.ContentType+"; charset=utf-8",
+			`{"status":"pass","output":"`+strings.Repeat("x", 100)+`"}`,
+		)
+
+		resp, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+		require.NoError(t, err)
+		assert.Equal(t, health.StatusPass, resp.Status)
+
+		_, err = health.CheckHealth(ctx,
+			health.WithMaxResponseSize(64),
+			health.WithURL(server.URL),
+		)
+
+		var maxBytesErr *http.MaxBytesError
+		require.ErrorAs(t, err, &maxBytesErr)
+		assert.EqualValues(t, 64, maxBytesErr.Limit)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusOK,
+			health.ContentType,
+			`{"status":"pass","unknown":true}`,
+		)
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+		require.NoError(t, err)
+
+		_, err = health.CheckHealth(ctx,
+			health.WithStrict(),
+			health.WithURL(server.URL),
+		)
+
+		var decodeErr *health.DecodeError
+		assert.ErrorAs(t, err, &decodeErr)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(http.StatusOK, health.ContentType, `{"status":`)
+
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var decodeErr *health.DecodeError
+		assert.ErrorAs(t, err, &decodeErr)
+	})
+
+	t.Run("failed check", func(t *testing.T) {
+		t.Parallel()
+
+		server := serve(
+			http.StatusServiceUnavailable,
+			health.ContentType,
+			`{"status":"fail","output":"broken"}`,
+		)
+
+		resp, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+		require.NoError(t, err)
+		assert.Equal(t, health.StatusFail, resp.Status)
+		assert.Equal(t, "broken", resp.Output)
+	})
+}
+
+func TestMainCode(t *testing.T) {
+	var (
+		ctx      = t.Context()
+		registry health.Registry
+		status   = health.StatusWarn
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: status}}
+	})
+
+	server := httptest.NewServer(health.NewHandler(
+		health.WithRegistry(&registry),
+	))
+	defer server.Close()
+
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	for _, tc := range []struct {
+		exit    int
+		options []health.MainOption
+		output  string
+	}{
+		{0, nil, `{"status":"warn"`},
+		{0, []health.MainOption{
+			health.WithOutputMode(health.OutputModeShort),
+		}, "warn\n"},
+		{health.ExitErr, []health.MainOption{
+			health.WithFailOnWarn(),
+			health.WithOutputMode(health.OutputModeSilent),
+		}, ""},
+		{health.ExitUser, []health.MainOption{
+			health.WithOutputMode(42),
+		}, ""},
+		{health.ExitUser, []health.MainOption{
+			health.WithCheckOptions(health.WithURL("ftp://example.com/")),
+		}, ""},
+		{health.ExitErr, []health.MainOption{
+			health.WithCheckOptions(
+				health.WithURL("unix:///nonexistent/health.sock"),
+			),
+		}, ""},
+	} {
+		file, err := os.CreateTemp(t.TempDir(), "")
+		require.NoError(t, err)
+
+		os.Stdout = file
+
+		exit := health.MainCode(ctx, append(
+			[]health.MainOption{
+				health.WithCheckOptions(health.WithURL(server.URL)),
+			},
+			tc.options...,
+		)...)
+
+		output, err := os.ReadFile(file.Name())
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		assert.Equal(t, tc.exit, exit)
+
+		if tc.output == "" {
+			assert.Empty(t, output)
+		} else {
+			assert.Contains(t, string(output), tc.output)
+		}
+	}
+}
+
+func TestCheckHealth_trace(t *testing.T) {
+	propagator := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(propagator)
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		ctx      = t.Context()
+		registry health.Registry
+		traceID  = trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}
+		member   = must(baggage.NewMember("probe", "test"))
+		seen     = make(chan context.Context, 1)
+	)
+
+	registry.RegisterFunc(ctx, "", func(ctx context.Context) []health.Check {
+		seen <- ctx
+		return []health.Check{{Status: health.StatusPass}}
+	})
+
+	server := httptest.NewServer(health.NewHandler(
+		health.WithRegistry(&registry),
+	))
+	defer server.Close()
+
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(
+		trace.SpanContextConfig{
+			SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+			TraceFlags: trace.FlagsSampled,
+			TraceID:    traceID,
+		},
+	))
+	ctx = baggage.ContextWithBaggage(ctx, must(baggage.New(member)))
+
+	_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+	require.NoError(t, err)
+
+	checkerCtx := <-seen
+	assert.Equal(t,
+		traceID,
+		trace.SpanContextFromContext(checkerCtx).TraceID(),
+	)
+	assert.Equal(t,
+		"test",
+		baggage.FromContext(checkerCtx).Member("probe").Value(),
+	)
+}
+
+func must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -140,6 +140,11 @@ func Main(ctx context.Context) int {
 		os.Stderr,
 	)))
 
+	// Before ADDRESS, which can include a port.
+	if port != 0 {
+		c.options = append(c.options, health.WithPort(port))
+	}
+
 	switch len(operands) {
 	case 0:
 
@@ -162,14 +167,18 @@ func Main(ctx context.Context) int {
 		return health.ExitUser
 	}
 
-	if port != 0 {
-		c.options = append(c.options, health.WithPort(port))
-	}
-
 	if timeout != 0 {
 		c.options = append(c.options, health.WithTimeout(timeout))
 	}
 
+	if err := health.ValidateOptions(c.options...); err != nil {
+		slog.ErrorContext(ctx, "invalid arguments",
+			slog.Any(errorKey, err),
+		)
+
+		return health.ExitUser
+	}
+
 	// Setting interval implies continuous
 	c.continuous = c.continuous || c.interval != 0
 	if c.continuous && c.interval == 0 {
@@ -259,33 +268,28 @@ func (c *cmd) makePrint() func(*health.Response) {
 func (c *cmd) run(ctx context.Context) int {
 	c.stats = make(map[string]uint64)
 
-	for {
+	if !c.continuous {
 		resp, err := health.CheckHealth(ctx, c.options...)
-		if err == nil {
-			c.stats[resp.Status.String()]++
-			c.printFunc(resp)
-		} else {
-			c.stats[errorKey]++
+		c.handle(ctx, resp, err)
 
-			slog.ErrorContext(ctx, "error",
-				slog.Any("error", err),
-			)
-		}
-
-		if !c.continuous {
-			for status, count := range c.stats {
-				if status == health.StatusPass.String() {
-					continue
-				}
-
-				if count > 0 {
-					return health.ExitErr
-				}
+		for status, count := range c.stats {
+			if status == health.StatusPass.String() {
+				continue
 			}
 
-			return 0
+			if count > 0 {
+				return health.ExitErr
+			}
 		}
 
+		return 0
+	}
+
+	for result := range health.Watch(ctx, c.interval,
+		health.WithCheckOptions(c.options...),
+	) {
+		c.handle(ctx, result.Response, result.Err)
+
 		if c.isatty {
 			var str []string
 
@@ -300,12 +304,27 @@ func (c *cmd) run(ctx context.Context) int {
 
 			fmt.Printf("\n---\n%s\n", strings.Join(str, ", "))
 		}
+	}
 
-		select {
-		case <-ctx.Done():
-			return 0
+	// Watching only stops by itself for invalid options.
+	if ctx.Err() == nil {
+		return health.ExitUser
+	}
 
-		case <-time.After(c.interval):
-		}
+	return 0
+}
+
+func (c *cmd) handle(ctx context.Context, resp *health.Response, err error) {
+	if err != nil {
+		c.stats[errorKey]++
+
+		slog.ErrorContext(ctx, "error",
+			slog.Any("error", err),
+		)
+
+		return
 	}
+
+	c.stats[resp.Status.String()]++
+	c.printFunc(resp)
 }
@@ -125,6 +125,31 @@ func TestMain(t *testing.T) {
 		},
 	})
 
+	f(T{
+		Args: []string{"--port", "1", "127.0.0.1:9999"},
+		Check: &health.Check{
+			Status: health.StatusPass,
+		},
+	})
+
+	f(T{
+		Args:   []string{"example.com:99999"},
+		Exit:   2,
+		Stderr: `invalid arguments`,
+	})
+
+	f(T{
+		Args:   []string{"ftp://example.com/"},
+		Exit:   2,
+		Stderr: `invalid arguments`,
+	})
+
+	f(T{
+		Args:   []string{"--continuous", "ftp://example.com/"},
+		Exit:   2,
+		Stderr: `invalid arguments`,
+	})
+
 	f(T{
 		Args:   []string{"too", "many", "operands"},
 		Exit:   2,
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"time"
+)
+
+const (
+	dialTimeout    = 10 * time.Second
+	maxReplyLength = 4096
+)
+
+var _ DialCheckOption = dialCheckOptionFunc(nil)
+
+// DialCheckOption is an optional configuration for [RegisterDial].
+type DialCheckOption interface {
+	applyDialCheck(*dialCheck)
+}
+
+// RegisterDial registers a checker that connects to an address, e.g. a message
+// broker or an SMTP relay. The network is ‘tcp’, ‘udp’ or ‘unix’ (or any other
+// supported by [net.Dialer.DialContext]).
+//
+// The time to connect is recorded (see [Check.SetObservedTime]). Note that
+// ‘connecting’ over UDP always succeeds, so a UDP check needs
+// [WithDialPayload] and [WithDialExpect] to be meaningful.
+func RegisterDial(
+	ctx context.Context,
+	network, address, name string,
+	base Check,
+	options ...DialCheckOption,
+) Registered {
+	if base.ComponentType == "" {
+		base.ComponentType = ComponentTypeComponent
+	}
+
+	c := dialCheck{
+		address: address,
+		base:    base,
+		network: network,
+		timeout: dialTimeout,
+	}
+
+	for _, option := range options {
+		option.applyDialCheck(&c)
+	}
+
+	return Register(ctx, name, namedFunc{
+		string:      network,
+		CheckerFunc: c.check,
+	})
+}
+
+// WithDialExpect is a [DialCheckOption] for [RegisterDial] to require a reply,
+// e.g. a banner, matching a regular expression. Up to 4 KiB is read.
+func WithDialExpect(re *regexp.Regexp) DialCheckOption {
+	return dialCheckOptionFunc(func(c *dialCheck) {
+		c.expect = re
+	})
+}
+
+// WithDialPayload is a [DialCheckOption] for [RegisterDial] to send a probe
+// once connected.
+func WithDialPayload(payload []byte) DialCheckOption {
+	return dialCheckOptionFunc(func(c *dialCheck) {
+		c.payload = payload
+	})
+}
+
+// WithDialTimeout is a [DialCheckOption] for [RegisterDial] to specify the
+// timeout for the whole check, i.e. connecting and any exchange. The default
+// is 10 s.
+func WithDialTimeout(timeout time.Duration) DialCheckOption {
+	return dialCheckOptionFunc(func(c *dialCheck) {
+		c.timeout = timeout
+	})
+}
+
+type dialCheck struct {
+	address string
+	base    Check
+	expect  *regexp.Regexp
+	network string
+	payload []byte
+	timeout time.Duration
+}
+
+func (c *dialCheck) check(ctx context.Context) []Check {
+	check := c.base
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	check.SetObservedTime(time.Since(start))
+
+	if err != nil {
+		check.Status = StatusFail
+		check.Output = err.Error()
+
+		return []Check{check}
+	}
+
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := c.exchange(conn); err != nil {
+		check.Status = StatusFail
+		check.Output = err.Error()
+	}
+
+	return []Check{check}
+}
+
+// exchange sends the payload, if any, and reads until the reply matches the
+// expected pattern, if any.
+func (c *dialCheck) exchange(conn net.Conn) error {
+	if len(c.payload) > 0 {
+		if _, err := conn.Write(c.payload); err != nil {
+			return fmt.Errorf("failed to send payload: %w", err)
+		}
+	}
+
+	if c.expect == nil {
+		return nil
+	}
+
+	var (
+		buf   = make([]byte, maxReplyLength)
+		reply []byte
+	)
+
+	for len(reply) < maxReplyLength {
+		n, err := conn.Read(buf[:maxReplyLength-len(reply)])
+		reply = append(reply, buf[:n]...)
+
+		if c.expect.Match(reply) {
+			return nil
+		}
+
+		if err != nil {
+			// Something was read; report what didn’t match.
+			if len(reply) > 0 && (errors.Is(err, io.EOF) ||
+				errors.Is(err, os.ErrDeadlineExceeded)) {
+				break
+			}
+
+			return fmt.Errorf("failed to read reply: %w", err)
+		}
+	}
+
+	return fmt.Errorf(
+		"reply %q doesn’t match %q",
+		oneLine(string(reply)),
+		c.expect,
+	)
+}
+
+type dialCheckOptionFunc func(*dialCheck)
+
+func (f dialCheckOptionFunc) applyDialCheck(c *dialCheck) {
+	f(c)
+}
@@ -0,0 +1,159 @@
+package health_test
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegisterDial(t *testing.T) {
+	ctx := t.Context()
+
+	// A tiny SMTP-like server: a banner, then an echo of each line.
+	serve := func(network, address string) string {
+		listener, err := net.Listen(network, address)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = listener.Close() })
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				go func() {
+					defer conn.Close()
+
+					_, _ = conn.Write([]byte("220 test ESMTP\r\n"))
+
+					scanner := bufio.NewScanner(conn)
+					for scanner.Scan() {
+						reply := "250 " + scanner.Text() + "\r\n"
+						_, _ = conn.Write([]byte(reply))
+					}
+				}()
+			}
+		}()
+
+		return listener.Addr().String()
+	}
+
+	var (
+		tcp  = serve("tcp", "127.0.0.1:0")
+		unix = serve("unix", filepath.Join(t.TempDir(), "test.sock"))
+	)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+
+	go func() {
+		buf := make([]byte, 512)
+
+		for {
+			n, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			_, _ = udp.WriteTo(append([]byte("pong "), buf[:n]...), addr)
+		}
+	}()
+
+	for _, tc := range []struct {
+		name             string
+		network, address string
+		options          []health.DialCheckOption
+		output           string
+		status           health.Status
+	}{
+		{
+			name:    "tcp",
+			network: "tcp",
+			address: tcp,
+			status:  health.StatusPass,
+		},
+		{
+			name:    "banner",
+			network: "tcp",
+			address: tcp,
+			options: []health.DialCheckOption{
+				health.WithDialExpect(regexp.MustCompile(`^220 `)),
+			},
+			status: health.StatusPass,
+		},
+		{
+			name:    "reply",
+			network: "unix",
+			address: unix,
+			options: []health.DialCheckOption{
+				health.WithDialPayload([]byte("EHLO test\r\n")),
+				health.WithDialExpect(regexp.MustCompile(`250 EHLO`)),
+			},
+			status: health.StatusPass,
+		},
+		{
+			name:    "mismatch",
+			network: "tcp",
+			address: tcp,
+			options: []health.DialCheckOption{
+				health.WithDialExpect(regexp.MustCompile(`^554 `)),
+				health.WithDialTimeout(100 * time.Millisecond),
+			},
+			output: `reply "220 test ESMTP" doesn’t match "^554 "`,
+			status: health.StatusFail,
+		},
+		{
+			name:    "refused",
+			network: "tcp",
+			address: closed.Addr().String(),
+			output:  "refused",
+			status:  health.StatusFail,
+		},
+		{
+			name:    "udp",
+			network: "udp",
+			address: udp.LocalAddr().String(),
+			options: []health.DialCheckOption{
+				health.WithDialPayload([]byte("ping")),
+				health.WithDialExpect(regexp.MustCompile(`^pong ping$`)),
+			},
+			status: health.StatusPass,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := health.RegisterDial(
+				ctx,
+				tc.network,
+				tc.address,
+				"dial",
+				health.Check{},
+				tc.options...,
+			)
+			defer r.Deregister()
+
+			resp, err := health.CheckNow(ctx)
+			require.NoError(t, err)
+			require.Len(t, resp.Checks[r.Name()], 1)
+
+			check := resp.Checks[r.Name()][0]
+			assert.Equal(t, tc.status, check.Status, check.Output)
+			assert.Contains(t, check.Output, tc.output)
+			assert.Equal(t, health.ComponentTypeComponent, check.ComponentType)
+			assert.Equal(t, "ns", check.ObservedUnit)
+		})
+	}
+}
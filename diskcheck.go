@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+const (
+	defaultDiskFail = 5
+	defaultDiskWarn = 10
+)
+
+var _ DiskCheckOption = diskCheckOptionFunc(nil)
+
+// DiskCheckOption is an optional configuration for [RegisterDisk].
+type DiskCheckOption interface {
+	applyDiskCheck(*diskCheck)
+}
+
+// RegisterDisk registers a checker for the filesystems of a set of paths. For
+// each path, with the path as component ID, there are Checks for:
+//
+//   - the free space in bytes (observed unit ‘B’)
+//   - the free space in percent (observed unit ‘%’)
+//   - the free inodes in percent (observed unit ‘% inodes’), unless the
+//     filesystem doesn’t have a fixed number of inodes
+//
+// The status depends on the thresholds; see [WithDiskSpace] and
+// [WithDiskInodes].
+//
+// Only supported on Linux, macOS and FreeBSD; elsewhere the checks fail.
+func RegisterDisk(
+	ctx context.Context,
+	paths []string,
+	name string,
+	base Check,
+	options ...DiskCheckOption,
+) Registered {
+	if base.ComponentType == "" {
+		base.ComponentType = ComponentTypeSystem
+	}
+
+	c := diskCheck{
+		base:       base,
+		inodesFail: defaultDiskFail,
+		inodesWarn: defaultDiskWarn,
+		paths:      paths,
+		spaceFail:  defaultDiskFail,
+		spaceWarn:  defaultDiskWarn,
+	}
+
+	for _, option := range options {
+		option.applyDiskCheck(&c)
+	}
+
+	return Register(ctx, name, namedFunc{
+		string:      "disk",
+		CheckerFunc: c.check,
+	})
+}
+
+// WithDiskInodes is a [DiskCheckOption] for [RegisterDisk] to warn or fail
+// when the free inodes are below percentages. A threshold of 0 is ignored. The
+// default is to warn below 10 % and fail below 5 %.
+func WithDiskInodes(warn, fail float64) DiskCheckOption {
+	return diskCheckOptionFunc(func(c *diskCheck) {
+		c.inodesWarn, c.inodesFail = warn, fail
+	})
+}
+
+// WithDiskSpace is a [DiskCheckOption] for [RegisterDisk] to warn or fail when
+// the free space is below percentages. A threshold of 0 is ignored. The
+// default is to warn below 10 % and fail below 5 %.
+func WithDiskSpace(warn, fail float64) DiskCheckOption {
+	return diskCheckOptionFunc(func(c *diskCheck) {
+		c.spaceWarn, c.spaceFail = warn, fail
+	})
+}
+
+type diskCheck struct {
+	base       Check
+	inodesFail float64
+	inodesWarn float64
+	paths      []string
+	spaceFail  float64
+	spaceWarn  float64
+}
+
+// diskUsage is the usage of a filesystem, as reported by statfs(2).
+type diskUsage struct {
+	Bytes, FreeBytes   uint64
+	Inodes, FreeInodes uint64
+}
+
+func (c *diskCheck) check(context.Context) []Check {
+	var checks []Check
+
+	for _, path := range c.paths {
+		check := c.base
+		check.ComponentID = path
+
+		usage, err := statfs(path)
+		if err != nil {
+			check.Status = StatusFail
+			check.Output = err.Error()
+			checks = append(checks, check)
+
+			continue
+		}
+
+		percent := percentage(usage.FreeBytes, usage.Bytes)
+		status := threshold(percent, c.spaceWarn, c.spaceFail)
+		output := fmt.Sprintf("%.2f %% free", percent)
+
+		bytesCheck := check
+		bytesCheck.ObservedValue = usage.FreeBytes
+		bytesCheck.ObservedUnit = "B"
+		bytesCheck.Status = WorstStatus(check.Status, status)
+		bytesCheck.Output = output
+
+		percentCheck := bytesCheck
+		percentCheck.ObservedValue = percent
+		percentCheck.ObservedUnit = "%"
+
+		checks = append(checks, bytesCheck, percentCheck)
+
+		if usage.Inodes == 0 {
+			continue
+		}
+
+		percent = percentage(usage.FreeInodes, usage.Inodes)
+
+		inodesCheck := check
+		inodesCheck.ObservedValue = percent
+		inodesCheck.ObservedUnit = "% inodes"
+		inodesCheck.Status = WorstStatus(
+			check.Status,
+			threshold(percent, c.inodesWarn, c.inodesFail),
+		)
+		inodesCheck.Output = fmt.Sprintf("%.2f %% inodes free", percent)
+
+		checks = append(checks, inodesCheck)
+	}
+
+	return checks
+}
+
+// percentage returns part of total in percent, rounded to two decimals.
+func percentage(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// threshold returns the status for a value given warn and fail thresholds
+// (below which the status is ‘warn’ or ‘fail’, respectively).
+func threshold(value, warn, fail float64) Status {
+	switch {
+	case fail > 0 && value < fail:
+		return StatusFail
+
+	case warn > 0 && value < warn:
+		return StatusWarn
+
+	default:
+		return StatusPass
+	}
+}
+
+type diskCheckOptionFunc func(*diskCheck)
+
+func (f diskCheckOptionFunc) applyDiskCheck(c *diskCheck) {
+	f(c)
+}
@@ -0,0 +1,14 @@
+//go:build !(darwin || freebsd || linux)
+
+package health
+
+import (
+	"errors"
+	"runtime"
+)
+
+func statfs(string) (diskUsage, error) {
+	return diskUsage{}, errors.New(
+		"disk checks not supported on " + runtime.GOOS,
+	)
+}
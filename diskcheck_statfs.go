@@ -0,0 +1,25 @@
+//go:build darwin || freebsd || linux
+
+package health
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func statfs(path string) (diskUsage, error) {
+	var st syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &st); err != nil {
+		return diskUsage{}, fmt.Errorf("statfs %s: %w", path, err)
+	}
+
+	bsize := uint64(st.Bsize)
+
+	return diskUsage{
+		Bytes:      uint64(st.Blocks) * bsize,
+		FreeBytes:  uint64(st.Bavail) * bsize,
+		FreeInodes: uint64(st.Ffree),
+		Inodes:     uint64(st.Files),
+	}, nil
+}
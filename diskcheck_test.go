@@ -0,0 +1,114 @@
+//go:build darwin || freebsd || linux
+
+package health_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegisterDisk(t *testing.T) {
+	var (
+		ctx  = t.Context()
+		dir  = t.TempDir()
+		none = filepath.Join(dir, "nonexistent")
+	)
+
+	for _, tc := range []struct {
+		name          string
+		options       []health.DiskCheckOption
+		space, inodes health.Status
+	}{
+		{
+			name: "pass",
+			options: []health.DiskCheckOption{
+				health.WithDiskInodes(0, 0),
+				health.WithDiskSpace(0, 0),
+			},
+			space:  health.StatusPass,
+			inodes: health.StatusPass,
+		},
+		{
+			name: "warn",
+			options: []health.DiskCheckOption{
+				health.WithDiskInodes(0, 0),
+				health.WithDiskSpace(101, 0),
+			},
+			space:  health.StatusWarn,
+			inodes: health.StatusPass,
+		},
+		{
+			name: "fail",
+			options: []health.DiskCheckOption{
+				health.WithDiskInodes(0, 0),
+				health.WithDiskSpace(101, 101),
+			},
+			space:  health.StatusFail,
+			inodes: health.StatusPass,
+		},
+		{
+			name: "inodes warn",
+			options: []health.DiskCheckOption{
+				health.WithDiskInodes(101, 0),
+				health.WithDiskSpace(0, 0),
+			},
+			space:  health.StatusPass,
+			inodes: health.StatusWarn,
+		},
+		{
+			name: "inodes fail",
+			options: []health.DiskCheckOption{
+				health.WithDiskInodes(101, 101),
+				health.WithDiskSpace(0, 0),
+			},
+			space:  health.StatusPass,
+			inodes: health.StatusFail,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := health.RegisterDisk(
+				ctx,
+				[]string{dir},
+				"disk",
+				health.Check{},
+				tc.options...,
+			)
+			defer r.Deregister()
+
+			resp, err := health.CheckNow(ctx)
+			require.NoError(t, err)
+
+			checks := resp.Checks[r.Name()]
+			require.GreaterOrEqual(t, len(checks), 2)
+
+			assert.Equal(t, dir, checks[0].ComponentID)
+			assert.Equal(t, health.ComponentTypeSystem, checks[0].ComponentType)
+			assert.Equal(t, "B", checks[0].ObservedUnit)
+			assert.Positive(t, checks[0].ObservedValue)
+			assert.Equal(t, tc.space, checks[0].Status)
+
+			assert.Equal(t, "%", checks[1].ObservedUnit)
+			assert.Equal(t, tc.space, checks[1].Status)
+
+			// Only for filesystems with a fixed number of inodes.
+			for _, check := range checks[2:] {
+				assert.Equal(t, "% inodes", check.ObservedUnit)
+				assert.Equal(t, tc.inodes, check.Status)
+			}
+		})
+	}
+
+	r := health.RegisterDisk(ctx, []string{none}, "disk", health.Check{})
+	defer r.Deregister()
+
+	resp, err := health.CheckNow(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.Checks[r.Name()], 1)
+	assert.Equal(t, health.StatusFail, resp.Checks[r.Name()][0].Status)
+	assert.Contains(t, resp.Checks[r.Name()][0].Output, "statfs")
+}
@@ -0,0 +1,299 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotse/go-health/internal"
+)
+
+var (
+	_ DNSCheckOption = dnsCheckOptionFunc(nil)
+
+	errUnsupportedRecordType = errors.New("unsupported record type")
+)
+
+// DNSCheckOption is an optional configuration for [RegisterDNS].
+type DNSCheckOption interface {
+	applyDNSCheck(*dnsCheck)
+}
+
+// RegisterDNS registers a checker that resolves a set of host names. There is
+// one Check per host name, with the host name as component ID and the lookup
+// time recorded (see [Check.SetObservedTime]).
+//
+// The host names are resolved concurrently, each with a timeout (see
+// [WithDNSTimeout]). A host name that can’t be resolved fails, unless other
+// host names could be resolved, in which case it only warns. A host name that
+// doesn’t resolve to the expected values (see [WithDNSExpect]) always fails.
+func RegisterDNS(
+	ctx context.Context,
+	hosts []string,
+	name string,
+	base Check,
+	options ...DNSCheckOption,
+) Registered {
+	if base.ComponentType == "" {
+		base.ComponentType = ComponentTypeComponent
+	}
+
+	c := dnsCheck{
+		base:     base,
+		hosts:    hosts,
+		resolver: net.DefaultResolver,
+		timeout:  dialTimeout,
+	}
+
+	for _, option := range options {
+		option.applyDNSCheck(&c)
+	}
+
+	return Register(ctx, name, namedFunc{
+		string:      "dns",
+		CheckerFunc: c.check,
+	})
+}
+
+// WithDNSExpect is a [DNSCheckOption] for [RegisterDNS] to require a host name
+// to resolve to (at least) some values, e.g. IP addresses or, depending on
+// [WithDNSRecordType], host names.
+func WithDNSExpect(host string, values ...string) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		if c.expect == nil {
+			c.expect = make(map[string][]string)
+		}
+
+		c.expect[host] = append(c.expect[host], values...)
+	})
+}
+
+// WithDNSRecordType is a [DNSCheckOption] for [RegisterDNS] to specify the
+// type of record to look up: ‘A’, ‘AAAA’, ‘CNAME’, ‘MX’, ‘NS’, ‘SRV’ or ‘TXT’.
+// The default is to look up IP addresses of any version.
+func WithDNSRecordType(recordType string) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		c.recordType = strings.ToUpper(recordType)
+	})
+}
+
+// WithDNSServer is a [DNSCheckOption] for [RegisterDNS] to query a specific
+// DNS server, e.g. ‘10.0.0.53:53’, instead of the system’s.
+func WithDNSServer(address string) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		c.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(
+				ctx context.Context,
+				network, _ string,
+			) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, address)
+			},
+		}
+	})
+}
+
+// WithDNSSlow is a [DNSCheckOption] for [RegisterDNS] to warn when a lookup
+// takes at least a threshold.
+func WithDNSSlow(threshold time.Duration) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		c.slow = threshold
+	})
+}
+
+// WithDNSTimeout is a [DNSCheckOption] for [RegisterDNS] to specify the
+// timeout for looking up each host name. The default is 10 s.
+func WithDNSTimeout(timeout time.Duration) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		c.timeout = timeout
+	})
+}
+
+// WithResolver is a [DNSCheckOption] for [RegisterDNS] to use a custom
+// [net.Resolver].
+func WithResolver(resolver *net.Resolver) DNSCheckOption {
+	return dnsCheckOptionFunc(func(c *dnsCheck) {
+		c.resolver = resolver
+	})
+}
+
+type dnsCheck struct {
+	base       Check
+	expect     map[string][]string
+	hosts      []string
+	recordType string
+	resolver   *net.Resolver
+	slow       time.Duration
+	timeout    time.Duration
+}
+
+func (c *dnsCheck) check(ctx context.Context) []Check {
+	var (
+		checks     = make([]Check, len(c.hosts))
+		unresolved = make([]bool, len(c.hosts))
+		wg         sync.WaitGroup
+	)
+
+	// Concurrently and with a timeout each, so that one hanging lookup
+	// doesn’t hold up the others.
+	for i, host := range c.hosts {
+		wg.Go(func() {
+			checks[i], unresolved[i] = c.checkHost(ctx, host)
+		})
+	}
+
+	wg.Wait()
+
+	// Partial success only warns, but only for failed lookups.
+	if slices.Contains(unresolved, false) {
+		for i := range checks {
+			if unresolved[i] {
+				checks[i].Status = StatusWarn
+			}
+		}
+	}
+
+	return checks
+}
+
+// checkHost checks a single host name. It also reports whether the lookup
+// failed, as opposed to e.g. not resolving to the expected values.
+func (c *dnsCheck) checkHost(ctx context.Context, host string) (Check, bool) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	check := c.base
+	check.ComponentID = host
+
+	start := time.Now()
+	values, err := c.lookup(ctx, host)
+	latency := time.Since(start)
+
+	check.SetObservedTime(latency)
+
+	unresolved := err != nil && !errors.Is(err, errUnsupportedRecordType)
+
+	if err == nil {
+		err = c.verify(host, values)
+	}
+
+	switch {
+	case err != nil:
+		check.Status = StatusFail
+		check.Output = err.Error()
+
+	case c.slow > 0 && latency >= c.slow:
+		check.Status = WorstStatus(check.Status, StatusWarn)
+		check.Output = "slow lookup: " + latency.String()
+	}
+
+	return check, unresolved
+}
+
+func (c *dnsCheck) lookup(ctx context.Context, host string) ([]string, error) {
+	switch c.recordType {
+	case "":
+		return c.lookupIP(ctx, "ip", host)
+
+	case "A":
+		return c.lookupIP(ctx, "ip4", host)
+
+	case "AAAA":
+		return c.lookupIP(ctx, "ip6", host)
+
+	case "CNAME":
+		cname, err := c.resolver.LookupCNAME(ctx, host)
+		return []string{cname}, err
+
+	case "MX":
+		mxs, err := c.resolver.LookupMX(ctx, host)
+
+		return internal.Map(mxs, func(mx *net.MX) string {
+			return mx.Host
+		}), err
+
+	case "NS":
+		nss, err := c.resolver.LookupNS(ctx, host)
+
+		return internal.Map(nss, func(ns *net.NS) string {
+			return ns.Host
+		}), err
+
+	case "SRV":
+		_, srvs, err := c.resolver.LookupSRV(ctx, "", "", host)
+
+		return internal.Map(srvs, func(srv *net.SRV) string {
+			return net.JoinHostPort(
+				srv.Target,
+				strconv.FormatUint(uint64(srv.Port), 10),
+			)
+		}), err
+
+	case "TXT":
+		return c.resolver.LookupTXT(ctx, host)
+
+	default:
+		return nil, fmt.Errorf("%w %q", errUnsupportedRecordType, c.recordType)
+	}
+}
+
+func (c *dnsCheck) lookupIP(
+	ctx context.Context,
+	network, host string,
+) ([]string, error) {
+	ips, err := c.resolver.LookupIP(ctx, network, host)
+
+	return internal.Map(ips, net.IP.String), err
+}
+
+// normalise returns a value in the form it’s compared in: host names (in
+// CNAME, MX, NS and SRV records) in lower case and without any trailing dot.
+// Other values, e.g. TXT records, are compared as they are.
+func (c *dnsCheck) normalise(value string) string {
+	hostName := func(str string) string {
+		return strings.ToLower(strings.TrimSuffix(str, "."))
+	}
+
+	switch c.recordType {
+	case "CNAME", "MX", "NS":
+		return hostName(value)
+
+	case "SRV":
+		if host, port, err := net.SplitHostPort(value); err == nil {
+			return net.JoinHostPort(hostName(host), port)
+		}
+	}
+
+	return value
+}
+
+// verify returns an error unless the values include all the expected ones.
+func (c *dnsCheck) verify(host string, values []string) error {
+	values = internal.Map(values, c.normalise)
+
+	for _, expected := range c.expect[host] {
+		if !slices.Contains(values, c.normalise(expected)) {
+			return fmt.Errorf(
+				"%s resolved to %s, not %s",
+				host,
+				strings.Join(values, ", "),
+				expected,
+			)
+		}
+	}
+
+	return nil
+}
+
+type dnsCheckOptionFunc func(*dnsCheck)
+
+func (f dnsCheckOptionFunc) applyDNSCheck(c *dnsCheck) {
+	f(c)
+}
@@ -0,0 +1,130 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegisterDNS(t *testing.T) {
+	ctx := t.Context()
+
+	// Resolves ‘localhost’ from /etc/hosts but nothing else.
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("no DNS here")
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		hosts    []string
+		options  []health.DNSCheckOption
+		statuses []health.Status
+	}{
+		{
+			name:  "resolved",
+			hosts: []string{"localhost"},
+			options: []health.DNSCheckOption{
+				health.WithDNSExpect("localhost", "127.0.0.1"),
+				health.WithDNSRecordType("a"),
+			},
+			statuses: []health.Status{health.StatusPass},
+		},
+		{
+			name:  "unexpected",
+			hosts: []string{"localhost"},
+			options: []health.DNSCheckOption{
+				health.WithDNSExpect("localhost", "192.0.2.1"),
+			},
+			statuses: []health.Status{health.StatusFail},
+		},
+		{
+			name:     "partial",
+			hosts:    []string{"localhost", "example.invalid"},
+			statuses: []health.Status{health.StatusPass, health.StatusWarn},
+		},
+		{
+			// Host names in /etc/hosts are case-insensitive.
+			name:  "partial unexpected",
+			hosts: []string{"localhost", "LOCALHOST"},
+			options: []health.DNSCheckOption{
+				health.WithDNSExpect("LOCALHOST", "192.0.2.1"),
+			},
+			statuses: []health.Status{health.StatusPass, health.StatusFail},
+		},
+		{
+			name:     "failed",
+			hosts:    []string{"example.invalid"},
+			statuses: []health.Status{health.StatusFail},
+		},
+		{
+			name:  "hanging",
+			hosts: []string{"example.invalid", "localhost"},
+			options: []health.DNSCheckOption{
+				health.WithDNSTimeout(50 * time.Millisecond),
+				health.WithResolver(&net.Resolver{
+					PreferGo: true,
+					Dial: func(
+						ctx context.Context,
+						_, _ string,
+					) (net.Conn, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					},
+				}),
+			},
+			statuses: []health.Status{health.StatusWarn, health.StatusPass},
+		},
+		{
+			name:  "slow",
+			hosts: []string{"localhost"},
+			options: []health.DNSCheckOption{
+				health.WithDNSSlow(1),
+			},
+			statuses: []health.Status{health.StatusWarn},
+		},
+		{
+			name:  "record type",
+			hosts: []string{"localhost"},
+			options: []health.DNSCheckOption{
+				health.WithDNSRecordType("HINFO"),
+			},
+			statuses: []health.Status{health.StatusFail},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := health.RegisterDNS(
+				ctx,
+				tc.hosts,
+				"dns",
+				health.Check{},
+				append(
+					[]health.DNSCheckOption{health.WithResolver(resolver)},
+					tc.options...,
+				)...,
+			)
+			defer r.Deregister()
+
+			resp, err := health.CheckNow(ctx)
+			require.NoError(t, err)
+
+			checks := resp.Checks[r.Name()]
+			require.Len(t, checks, len(tc.hosts))
+
+			for i, check := range checks {
+				assert.Equal(t, tc.hosts[i], check.ComponentID)
+				assert.Equal(t, tc.statuses[i], check.Status, check.Output)
+				assert.Equal(t, "ns", check.ObservedUnit)
+			}
+		})
+	}
+}
@@ -7,10 +7,10 @@ simple way to set up a health check for ‘anything’.
 As soon as any health checks are registered a summary of them is served at
 http://0.0.0.0:9999.
 
-For services there is `HealthCheckCommand()` to put (early) in `main()`, e.g:
+For services there is Main to put (early) in `main()`, e.g:
 
 	if len(os.Args) >= 2 && os.Args[1] == "healthcheck" {
-		health.Main()
+		health.Main(ctx)
 	}
 
 Docker images can then use the following:
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+)
+
+const excerptLength = 512
+
+var (
+	// ErrUnreachable is returned (wrapped) by [CheckHealth] when the server
+	// can’t be reached, e.g. because the connection is refused or times out.
+	// A failed TLS handshake, e.g. with an untrusted certificate, isn’t
+	// ‘unreachable’.
+	ErrUnreachable = errors.New("health server unreachable")
+
+	_ error = (*ContentTypeError)(nil)
+	_ error = (*DecodeError)(nil)
+	_ error = (*StatusError)(nil)
+)
+
+// ContentTypeError is returned by [CheckHealth] when the server responds with
+// an unexpected media type.
+type ContentTypeError struct {
+	ContentType string
+}
+
+func (err *ContentTypeError) Error() string {
+	return fmt.Sprintf("unexpected content type %q", err.ContentType)
+}
+
+// DecodeError is returned by [CheckHealth] when the response body can’t be
+// decoded.
+type DecodeError struct {
+	Err error
+}
+
+func (err *DecodeError) Error() string {
+	return "failed to decode health response: " + err.Err.Error()
+}
+
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// StatusError is returned by [CheckHealth] when the server responds with an
+// unexpected HTTP status code and without a valid health response, i.e. one
+// with a ‘status’.
+type StatusError struct {
+	// Body is an excerpt of the beginning of the response body.
+	Body       string
+	StatusCode int
+}
+
+func (err *StatusError) Error() string {
+	str := fmt.Sprintf(
+		"unexpected HTTP status %d %s",
+		err.StatusCode,
+		http.StatusText(err.StatusCode),
+	)
+
+	if body := oneLine(err.Body); body != "" {
+		str += ": " + body
+	}
+
+	return str
+}
+
+// readResponse reads a Response from an HTTP response, turning problems into
+// the appropriate errors.
+func (c *config) readResponse(httpResp *http.Response) (*Response, error) {
+	ok := httpResp.StatusCode >= 200 && httpResp.StatusCode < 300
+	body := http.MaxBytesReader(nil, httpResp.Body, c.MaxResponseSize)
+
+	err := checkContentType(httpResp.Header.Get(headers.ContentType))
+	if err != nil {
+		if ok {
+			return nil, err
+		}
+
+		return nil, newStatusError(httpResp.StatusCode, body)
+	}
+
+	if ok {
+		resp, err := c.decode(body)
+		if err != nil {
+			return nil, &DecodeError{err}
+		}
+
+		return resp, nil
+	}
+
+	// E.g. a 500 with a valid health response body is a failed health check,
+	// not an error. Anything else, e.g. a proxy’s JSON error, is.
+	bajts, err := io.ReadAll(body)
+	if err != nil || !hasStatus(bajts) {
+		return nil, newStatusError(httpResp.StatusCode, bytes.NewReader(bajts))
+	}
+
+	resp, err := c.decode(bytes.NewReader(bajts))
+	if err != nil {
+		return nil, newStatusError(httpResp.StatusCode, bytes.NewReader(bajts))
+	}
+
+	return resp, nil
+}
+
+// decode decodes a Response, rejecting unknown fields in strict mode.
+func (c *config) decode(r io.Reader) (*Response, error) {
+	decoder := json.NewDecoder(r)
+
+	if c.Strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	var resp Response
+
+	if err := decoder.Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// checkContentType returns a [ContentTypeError] unless the content type is
+// empty or JSON, in UTF-8.
+func checkContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil ||
+		(mediaType != ContentType && mediaType != "application/json") {
+		return &ContentTypeError{contentType}
+	}
+
+	if charset, ok := params["charset"]; ok &&
+		!strings.EqualFold(charset, "utf-8") &&
+		!strings.EqualFold(charset, "utf8") {
+		return &ContentTypeError{contentType}
+	}
+
+	return nil
+}
+
+// hasStatus returns true if a body is a JSON object with a ‘status’.
+func hasStatus(body []byte) bool {
+	var object struct {
+		Status *json.RawMessage `json:"status"`
+	}
+
+	return json.Unmarshal(body, &object) == nil && object.Status != nil
+}
+
+func newStatusError(statusCode int, body io.Reader) *StatusError {
+	bajts, _ := io.ReadAll(io.LimitReader(body, excerptLength))
+
+	return &StatusError{
+		Body:       strings.ToValidUTF8(string(bajts), ""),
+		StatusCode: statusCode,
+	}
+}
+
+// unreachable wraps an error from sending a request in [ErrUnreachable],
+// unless the server was reached but the TLS handshake failed, e.g. because its
+// certificate isn’t trusted.
+func unreachable(err error) error {
+	var (
+		alert        tls.AlertError
+		certificate  *tls.CertificateVerificationError
+		invalid      x509.CertificateInvalidError
+		hostname     x509.HostnameError
+		opErr        *net.OpError
+		recordHeader tls.RecordHeaderError
+		unknown      x509.UnknownAuthorityError
+	)
+
+	switch {
+	case errors.As(err, &alert),
+		errors.As(err, &certificate),
+		errors.As(err, &invalid),
+		errors.As(err, &hostname),
+		errors.As(err, &recordHeader),
+		errors.As(err, &unknown),
+		// An alert from the server, e.g. rejecting the client certificate.
+		errors.As(err, &opErr) && opErr.Op == "remote error":
+		return err
+
+	default:
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+}
@@ -0,0 +1,230 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	sauté "gitlab.com/biffen/saute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ContentTypeEventStream is the media (MIME) type of the Server-Sent Events
+// stream served by [HandleEvents].
+const ContentTypeEventStream = "text/event-stream"
+
+const (
+	eventsHeartbeat = 15 * time.Second
+	eventsInterval  = time.Second
+)
+
+// HandleEvents streams health responses over HTTP as Server-Sent Events. The
+// full Response is sent as a ‘health’ event on connect and then again every time
+// the overall status or the status of any check changes. Heartbeat comments are
+// sent in between to keep the connection alive.
+//
+// The checks are run once a second while any client is connected, however many
+// clients there are.
+//
+// The stream ends when the client disconnects.
+//
+// HandleEvents uses the default configuration of [NewHandler].
+func HandleEvents(w http.ResponseWriter, req *http.Request) {
+	defaultHandler.serveEvents(w, req)
+}
+
+func (h *handler) serveEvents(w http.ResponseWriter, req *http.Request) {
+	ctx, span := sauté.TraceFunc(extract(req), nil)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set(headers.CacheControl, "no-cache")
+	w.Header().Set(headers.ContentType, ContentTypeEventStream)
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	updates, unsubscribe := h.events.subscribe(h.registry, h.probe)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	var last *Response
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case resp := <-updates:
+			resp = h.restrict(req, resp)
+			if !changed(last, &resp) {
+				continue
+			}
+
+			last = &resp
+
+			heartbeat.Reset(eventsHeartbeat)
+
+			if err := writeEvent(w, &resp); err != nil {
+				return
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// poller runs the checks for all of a handler’s event streams, so that the
+// number of connected clients doesn’t affect how often the checks run. It polls
+// only while there are subscribers.
+type poller struct {
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	latest      *Response
+	subscribers map[chan Response]struct{}
+}
+
+// subscribe returns a channel receiving the latest Response, first as soon as
+// there is one and then after every poll, and a function to unsubscribe.
+func (p *poller) subscribe(
+	registry *Registry,
+	probe Probe,
+) (<-chan Response, func()) {
+	ch := make(chan Response, 1)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.subscribers == nil {
+		p.subscribers = make(map[chan Response]struct{})
+	}
+
+	p.subscribers[ch] = struct{}{}
+
+	if p.latest != nil {
+		ch <- *p.latest
+	}
+
+	if p.cancel == nil {
+		var ctx context.Context
+
+		ctx, p.cancel = context.WithCancel(context.Background())
+
+		go p.poll(ctx, registry, probe)
+	}
+
+	return ch, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		delete(p.subscribers, ch)
+
+		if len(p.subscribers) == 0 && p.cancel != nil {
+			p.cancel()
+			p.cancel = nil
+			p.latest = nil
+		}
+	}
+}
+
+func (p *poller) poll(ctx context.Context, registry *Registry, probe Probe) {
+	ticker := time.NewTicker(eventsInterval)
+	defer ticker.Stop()
+
+	for {
+		if resp, err := registry.CheckProbe(ctx, probe); err == nil {
+			p.publish(ctx, &resp)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish sends a Response to all subscribers, replacing any they haven’t
+// received yet.
+func (p *poller) publish(ctx context.Context, resp *Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Stopped while checking.
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.latest = resp
+
+	for ch := range p.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- *resp
+	}
+}
+
+// changed returns true if the overall status or the status of any check differs
+// between two responses.
+func changed(old, resp *Response) bool {
+	if old == nil || old.Status != resp.Status ||
+		len(old.Checks) != len(resp.Checks) {
+		return true
+	}
+
+	for name, checks := range resp.Checks {
+		oldChecks, ok := old.Checks[name]
+		if !ok || !slices.EqualFunc(oldChecks, checks, func(a, b Check) bool {
+			return a.Status == b.Status
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeEvent(w http.ResponseWriter, resp *Response) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("event: health\ndata: ")
+
+	if _, err := resp.Write(&buf); err != nil {
+		return err
+	}
+
+	buf.WriteString("\n\n")
+
+	_, err := buf.WriteTo(w)
+
+	return err
+}
@@ -0,0 +1,121 @@
+package health_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestHandleEvents(t *testing.T) {
+	var (
+		ctx    = t.Context()
+		status atomic.Uint32
+	)
+
+	r := health.RegisterFunc(ctx, t.Name(), func(context.Context) []health.Check {
+		return []health.Check{{Status: health.Status(status.Load())}}
+	})
+	defer r.Deregister()
+
+	server := httptest.NewServer(http.HandlerFunc(health.HandleEvents))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	httpResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer httpResp.Body.Close()
+
+	assert.Equal(t, health.ContentTypeEventStream, httpResp.Header.Get(headers.ContentType))
+
+	scanner := bufio.NewScanner(httpResp.Body)
+
+	next := func() *health.Response {
+		t.Helper()
+
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			resp, err := health.ReadResponse(strings.NewReader(data))
+			require.NoError(t, err)
+
+			return resp
+		}
+
+		require.NoError(t, scanner.Err())
+		t.FailNow()
+
+		return nil
+	}
+
+	assert.Equal(t, health.StatusPass, next().Status)
+
+	status.Store(uint32(health.StatusFail))
+
+	resp := next()
+	assert.Equal(t, health.StatusFail, resp.Status)
+	require.Len(t, resp.Checks[r.Name()], 1)
+	assert.Equal(t, health.StatusFail, resp.Checks[r.Name()][0].Status)
+}
+
+func TestHandleEvents_shared(t *testing.T) {
+	t.Parallel()
+
+	var (
+		checks   atomic.Int32
+		ctx      = t.Context()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		checks.Add(1)
+		return []health.Check{{Status: health.StatusPass}}
+	})
+
+	server := httptest.NewServer(health.NewHandler(
+		health.WithRegistry(&registry),
+	))
+	defer server.Close()
+
+	for range 5 {
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodGet,
+			server.URL+"/events",
+			nil,
+		)
+		require.NoError(t, err)
+
+		httpResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		defer httpResp.Body.Close()
+
+		scanner := bufio.NewScanner(httpResp.Body)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "data: ") {
+				break
+			}
+		}
+
+		require.NoError(t, scanner.Err())
+	}
+
+	// One check on the first connect, and maybe one more on a tick.
+	assert.LessOrEqual(t, checks.Load(), int32(2))
+}
@@ -0,0 +1,4 @@
+package health
+
+// Backoff exports backoff for tests.
+var Backoff = backoff
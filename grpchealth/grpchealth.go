@@ -0,0 +1,279 @@
+/*
+Package grpchealth serves health checks using the [gRPC Health Checking
+Protocol], backed by a [health.Registry].
+
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, grpchealth.NewServer())
+
+The empty service name is the overall status of [health.ProbeReadiness]. Every
+registered health checker is also a service, by its registration name. More
+services can be configured with [WithProbe] and [WithService]. A service
+depending on a health checker that isn’t registered is unknown.
+
+A [health.Status] of ‘pass’ or ‘warn’ is SERVING and ‘fail’ is NOT_SERVING.
+
+[gRPC Health Checking Protocol]: https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+*/
+package grpchealth
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	sauté "gitlab.com/biffen/saute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/dotse/go-health"
+)
+
+var (
+	_ Option                      = optionFunc(nil)
+	_ grpc_health_v1.HealthServer = (*Server)(nil)
+)
+
+// Option is an optional configuration for [NewServer].
+type Option interface {
+	apply(*Server)
+}
+
+// WithInterval is an [Option] for [NewServer] to specify how often health is
+// checked while watching. The default is one second.
+func WithInterval(interval time.Duration) Option {
+	return optionFunc(func(s *Server) {
+		s.interval = interval
+	})
+}
+
+// WithProbe is an [Option] for [NewServer] to map a service name to the
+// overall status of a [health.Probe].
+func WithProbe(service string, probe health.Probe) Option {
+	return optionFunc(func(s *Server) {
+		s.probes[service] = probe
+	})
+}
+
+// WithRegistry is an [Option] for [NewServer] to specify the
+// [health.Registry] whose checks to serve. The default is the default
+// registry.
+func WithRegistry(registry *health.Registry) Option {
+	return optionFunc(func(s *Server) {
+		s.registry = registry
+	})
+}
+
+// WithService is an [Option] for [NewServer] to map a service name to the
+// worst status of any number of registered health checkers, by registration
+// name.
+func WithService(service string, names ...string) Option {
+	return optionFunc(func(s *Server) {
+		s.services[service] = names
+	})
+}
+
+// Server implements [grpc_health_v1.HealthServer].
+type Server struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	interval time.Duration
+	probes   map[string]health.Probe
+	registry *health.Registry
+	services map[string][]string
+}
+
+// NewServer creates a new [Server].
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		interval: time.Second,
+		probes: map[string]health.Probe{
+			"": health.ProbeReadiness,
+		},
+		registry: health.DefaultRegistry(),
+		services: make(map[string][]string),
+	}
+
+	for _, option := range options {
+		option.apply(s)
+	}
+
+	return s
+}
+
+// Check implements [grpc_health_v1.HealthServer].
+func (s *Server) Check(
+	ctx context.Context,
+	req *grpc_health_v1.HealthCheckRequest,
+) (*grpc_health_v1.HealthCheckResponse, error) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	servingStatus, err := s.check(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: servingStatus,
+	}, nil
+}
+
+// List implements [grpc_health_v1.HealthServer].
+func (s *Server) List(
+	ctx context.Context,
+	_ *grpc_health_v1.HealthListRequest,
+) (*grpc_health_v1.HealthListResponse, error) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	resp, err := s.registry.CheckNow(ctx)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	services := slices.Concat(
+		slices.Collect(maps.Keys(s.probes)),
+		slices.Collect(maps.Keys(s.services)),
+		slices.Collect(maps.Keys(resp.Checks)),
+	)
+
+	list := &grpc_health_v1.HealthListResponse{
+		Statuses: make(
+			map[string]*grpc_health_v1.HealthCheckResponse,
+			len(services),
+		),
+	}
+
+	for _, service := range services {
+		servingStatus, err := s.status(&resp, service)
+		if err != nil {
+			servingStatus = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+
+		list.Statuses[service] = &grpc_health_v1.HealthCheckResponse{
+			Status: servingStatus,
+		}
+	}
+
+	return list, nil
+}
+
+// Watch implements [grpc_health_v1.HealthServer].
+func (s *Server) Watch(
+	req *grpc_health_v1.HealthCheckRequest,
+	stream grpc.ServerStreamingServer[grpc_health_v1.HealthCheckResponse],
+) error {
+	ctx, span := sauté.TraceFunc(stream.Context(), nil)
+	defer span.End()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_ServingStatus(-1)
+
+	for {
+		servingStatus, err := s.check(ctx, req.GetService())
+
+		switch status.Code(err) {
+		case codes.OK:
+
+		case codes.NotFound:
+			servingStatus = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+
+		default:
+			return err
+		}
+
+		if servingStatus != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{
+				Status: servingStatus,
+			}); err != nil {
+				return err
+			}
+
+			last = servingStatus
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) check(
+	ctx context.Context,
+	service string,
+) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if probe, ok := s.probes[service]; ok {
+		resp, err := s.registry.CheckProbe(ctx, probe)
+		if err != nil {
+			return grpc_health_v1.HealthCheckResponse_UNKNOWN,
+				status.FromContextError(err).Err()
+		}
+
+		return servingStatus(resp.Status), nil
+	}
+
+	resp, err := s.registry.CheckNow(ctx)
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN,
+			status.FromContextError(err).Err()
+	}
+
+	return s.status(&resp, service)
+}
+
+// status derives the status of a service from a Response with the checks of
+// all health checkers. A service mapped to an unknown health checker is
+// unknown itself.
+func (s *Server) status(
+	resp *health.Response,
+	service string,
+) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if probe, ok := s.probes[service]; ok {
+		return servingStatus(s.registry.ApplyProbe(*resp, probe).Status), nil
+	}
+
+	names, ok := s.services[service]
+	if !ok {
+		names = []string{service}
+	}
+
+	worst := health.StatusPass
+
+	for _, name := range names {
+		checks, ok := resp.Checks[name]
+		if !ok {
+			return grpc_health_v1.HealthCheckResponse_UNKNOWN,
+				status.Errorf(codes.NotFound, "unknown service %q", service)
+		}
+
+		for _, check := range checks {
+			worst = health.WorstStatus(worst, check.Status)
+		}
+	}
+
+	return servingStatus(worst), nil
+}
+
+func servingStatus(
+	status health.Status,
+) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if status == health.StatusPass || status == health.StatusWarn {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
+
+type optionFunc func(*Server)
+
+func (f optionFunc) apply(s *Server) {
+	f(s)
+}
@@ -0,0 +1,132 @@
+package grpchealth_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dotse/go-health"
+	"github.com/dotse/go-health/grpchealth"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = t.Context()
+		db       atomic.Uint32
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "db", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.Status(db.Load())}}
+	})
+	registry.RegisterFunc(ctx, "cache", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusWarn}}
+	})
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, grpchealth.NewServer(
+		grpchealth.WithProbe("liveness", health.ProbeLiveness),
+		grpchealth.WithRegistry(&registry),
+		grpchealth.WithService("queue", "db", "mq"),
+		grpchealth.WithService("storage", "db", "cache"),
+	))
+
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	check := func(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+		t.Helper()
+
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+			Service: service,
+		})
+		require.NoError(t, err)
+
+		return resp.GetStatus()
+	}
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check(""))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check("db"))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check("storage"))
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: "nope",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: "queue",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Watch(watchCtx, &grpc_health_v1.HealthCheckRequest{
+		Service: "db",
+	})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	db.Store(uint32(health.StatusFail))
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check(""))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check("storage"))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check("cache"))
+
+	registry.SetDraining(ctx, true)
+	db.Store(uint32(health.StatusPass))
+
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check(""))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check("liveness"))
+
+	list, err := client.List(ctx, &grpc_health_v1.HealthListRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetStatuses(), 6)
+	assert.Equal(t,
+		grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN,
+		list.GetStatuses()["queue"].GetStatus(),
+	)
+	assert.Equal(t,
+		grpc_health_v1.HealthCheckResponse_SERVING,
+		list.GetStatuses()["liveness"].GetStatus(),
+	)
+	assert.Equal(t,
+		grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		list.GetStatuses()[""].GetStatus(),
+	)
+}
@@ -0,0 +1,358 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/go-http-utils/negotiator"
+	sauté "gitlab.com/biffen/saute"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// DetailFull is the default [Detail]; responses include all checks.
+	DetailFull Detail = iota
+	// DetailStatus only reveals the overall status in responses.
+	DetailStatus
+)
+
+var (
+	_ HandlerOption = handlerOptionFunc(nil)
+	_ http.Handler  = (*handler)(nil)
+
+	defaultHandler = NewHandler().(*handler)
+)
+
+// Detail is how much a handler reveals in its responses.
+type Detail uint8
+
+// HandlerOption is an optional configuration for [NewHandler].
+type HandlerOption interface {
+	applyHandler(*handler)
+}
+
+// HandleHTTP serves a health response over HTTP. It supports the GET, HEAD and
+// OPTIONS methods as well as content negotiation.
+//
+// Browsers asking for [ContentTypeHTML] get a status page. The query parameter
+// ‘refresh’ can be set to a number of seconds to make the page reload itself.
+//
+// Clients asking for [ContentTypeText], or setting the query parameter
+// ‘verbose’, get a plain text report similar to Kubernetes’ ‘/healthz?verbose’.
+// The query parameter doesn’t override an Accept header that rules out all the
+// supported content types.
+//
+// HandleHTTP uses the default configuration of [NewHandler], but serves the
+// health response regardless of the request path.
+func HandleHTTP(w http.ResponseWriter, req *http.Request) {
+	defaultHandler.serveHealth(w, req, defaultHandler.probe)
+}
+
+// NewHandler returns an [net/http.Handler] serving health responses (see
+// [HandleHTTP]) at the path prefix (default ‘/’) and Server-Sent Events (see
+// [HandleEvents]) at the path prefix followed by ‘/events’.
+//
+// Responses for [ProbeLiveness] and [ProbeReadiness] are served at the path
+// prefix followed by ‘/livez’ and ‘/readyz’, respectively. The path prefix
+// itself serves [ProbeReadiness] unless changed with [WithProbe].
+//
+// Without options it serves checks from the default registry, i.e. the ones
+// registered with [Register], with full detail.
+func NewHandler(options ...HandlerOption) http.Handler {
+	h := &handler{
+		detail:   DetailFull,
+		formats:  slices.Clone(formats),
+		registry: &defaultRegistry,
+		statusCodes: map[Status]int{
+			StatusPass: http.StatusOK,
+			StatusWarn: http.StatusOK,
+			StatusFail: http.StatusInternalServerError,
+		},
+	}
+
+	for _, option := range options {
+		option.applyHandler(h)
+	}
+
+	return h
+}
+
+// WithDetail is a [HandlerOption] for [NewHandler] to specify how much detail
+// to reveal.
+func WithDetail(detail Detail) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.detail = detail
+	})
+}
+
+// WithFormats is a [HandlerOption] for [NewHandler] to specify which media
+// types to offer in content negotiation, in order of preference. Unsupported
+// types are ignored. The default is [ContentType], ‘application/json’,
+// [ContentTypeHTML] and [ContentTypeText].
+func WithFormats(mediaTypes ...string) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.formats = nil
+
+		for _, mediaType := range mediaTypes {
+			if slices.Contains(formats, mediaType) &&
+				!slices.Contains(h.formats, mediaType) {
+				h.formats = append(h.formats, mediaType)
+			}
+		}
+	})
+}
+
+// WithPrefix is a [HandlerOption] for [NewHandler] to specify the path prefix
+// the handler is mounted at, e.g. ‘/internal/health’.
+func WithPrefix(prefix string) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.prefix = strings.TrimSuffix(prefix, "/")
+	})
+}
+
+// WithProbe is a [HandlerOption] for [NewHandler] to specify which [Probe] to
+// serve at the path prefix.
+func WithProbe(probe Probe) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.probe = probe
+	})
+}
+
+// WithRegistry is a [HandlerOption] for [NewHandler] to specify the [Registry]
+// whose checks to serve.
+func WithRegistry(registry *Registry) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.registry = registry
+	})
+}
+
+// WithStatusCode is a [HandlerOption] for [NewHandler] to specify the HTTP
+// status code to respond with for a [Status]. The defaults are 200 for ‘pass’
+// and ‘warn’ and 500 for ‘fail’.
+func WithStatusCode(status Status, code int) HandlerOption {
+	return handlerOptionFunc(func(h *handler) {
+		h.statusCodes[status] = code
+	})
+}
+
+// formats are the supported media types, in the default order of preference.
+var formats = []string{
+	ContentType,
+	"application/json",
+	ContentTypeHTML,
+	ContentTypeText,
+}
+
+type handler struct {
+	admin          bool
+	allowlists     map[Detail][]netip.Prefix
+	authorizers    []Authorizer
+	detail         Detail
+	events         poller
+	formats        []string
+	prefix         string
+	probe          Probe
+	registry       *Registry
+	statusCodes    map[Status]int
+	trustedProxies []netip.Prefix
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path, ok := strings.CutPrefix(req.URL.Path, h.prefix)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	if _, ok := h.access(req); !ok {
+		http.Error(w, "", http.StatusForbidden)
+		return
+	}
+
+	switch path {
+	case "", "/":
+		h.serveHealth(w, req, h.probe)
+
+	case "/livez":
+		h.serveHealth(w, req, ProbeLiveness)
+
+	case "/readyz":
+		h.serveHealth(w, req, ProbeReadiness)
+
+	case "/events":
+		if req.Method != http.MethodGet {
+			w.Header().Set(headers.Allow, http.MethodGet)
+			http.Error(w, "", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		h.serveEvents(w, req)
+
+	default:
+		if rest, ok := strings.CutPrefix(path, "/admin"); h.admin && ok &&
+			(rest == "" || strings.HasPrefix(rest, "/")) {
+			h.serveAdmin(w, req, rest)
+			return
+		}
+
+		http.NotFound(w, req)
+	}
+}
+
+// check gets a response for a probe from the registry, with the level of
+// detail allowed for the request.
+func (h *handler) check(req *http.Request, probe Probe) (Response, error) {
+	resp, err := h.registry.CheckProbe(req.Context(), probe)
+	if err != nil {
+		return resp, err
+	}
+
+	return h.restrict(req, resp), nil
+}
+
+// restrict strips a Response of the details the client may not see.
+func (h *handler) restrict(req *http.Request, resp Response) Response {
+	if detail, _ := h.access(req); detail == DetailStatus {
+		return Response{
+			Status: resp.Status,
+			Output: resp.Output,
+		}
+	}
+
+	return resp
+}
+
+func (h *handler) serveHealth(
+	w http.ResponseWriter,
+	req *http.Request,
+	probe Probe,
+) {
+	ctx, span := sauté.TraceFunc(extract(req), nil)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+
+	errorStatus := func(err error, status int) {
+		if err == nil {
+			http.Error(w, "", status)
+			span.SetStatus(codes.Error, http.StatusText(status))
+
+			return
+		}
+
+		http.Error(w, err.Error(), status)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	switch req.Method {
+	case http.MethodOptions:
+		w.Header().Set(headers.Allow, strings.Join([]string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+		}, ", "))
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+
+	case http.MethodGet, http.MethodHead:
+
+	default:
+		errorStatus(nil, http.StatusMethodNotAllowed)
+		return
+	}
+
+	negotiator := negotiator.New(req.Header)
+
+	if negotiator.Charset("UTF-8") == "" {
+		http.Error(w, "", http.StatusNotAcceptable)
+		return
+	}
+
+	ct := negotiator.Type(h.formats...)
+
+	if ct == "" {
+		errorStatus(nil, http.StatusNotAcceptable)
+		return
+	}
+
+	if req.URL.Query().Has("verbose") &&
+		slices.Contains(h.formats, ContentTypeText) {
+		ct = ContentTypeText
+	}
+
+	w.Header().Set(headers.ContentEncoding, "UTF-8")
+	w.Header().Set(headers.ContentType, ct)
+
+	resp, err := h.check(req, probe)
+	if err != nil {
+		errorStatus(err, http.StatusInternalServerError)
+		return
+	}
+
+	if req.Method == http.MethodHead {
+		w.Header().Set(headers.ContentLength, "0")
+	}
+
+	if code, ok := h.statusCodes[resp.Status]; ok && code != http.StatusOK {
+		w.WriteHeader(code)
+	}
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	switch ct {
+	case ContentTypeHTML:
+		err = writeHTML(w, &resp, refresh(req))
+
+	case ContentTypeText:
+		err = writeText(w, &resp)
+
+	default:
+		_, err = resp.Write(w)
+	}
+
+	if err != nil {
+		errorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+func refresh(req *http.Request) time.Duration {
+	str := req.URL.Query().Get("refresh")
+	if str == "" {
+		return 0
+	}
+
+	u, err := strconv.ParseUint(str, 10, 16)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(u) * time.Second
+}
+
+type handlerOptionFunc func(*handler)
+
+func (f handlerOptionFunc) applyHandler(h *handler) {
+	f(h)
+}
+
+// extract returns the request’s context with any trace context and baggage
+// propagated from the client (see [CheckHealth]).
+func extract(req *http.Request) context.Context {
+	return otel.GetTextMapPropagator().Extract(
+		req.Context(),
+		propagation.HeaderCarrier(req.Header),
+	)
+}
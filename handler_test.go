@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "db", func(context.Context) []health.Check {
+		return []health.Check{{
+			Status: health.StatusWarn,
+			Output: "slow",
+		}}
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/internal/health/", health.NewHandler(
+		health.WithRegistry(&registry),
+		health.WithPrefix("/internal/health/"),
+	))
+	mux.Handle("/status/", health.NewHandler(
+		health.WithDetail(health.DetailStatus),
+		health.WithFormats(health.ContentTypeText, "image/png"),
+		health.WithPrefix("/status"),
+		health.WithRegistry(&registry),
+		health.WithStatusCode(health.StatusWarn, http.StatusTeapot),
+	))
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set(headers.Accept, accept)
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := get("/internal/health/", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, health.ContentType, w.Header().Get(headers.ContentType))
+
+	resp, err := health.ReadResponse(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+	assert.Equal(t, "slow", resp.Checks["db"][0].Output)
+
+	assert.Equal(t, http.StatusNotFound, get("/internal/health/nope", "").Code)
+
+	w = get("/status/", "")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, health.ContentTypeText, w.Header().Get(headers.ContentType))
+	assert.Equal(t, "health check passed with warnings\n", w.Body.String())
+
+	assert.Equal(t, http.StatusNotAcceptable, get("/status/", health.ContentType).Code)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	r := registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusFail}}
+	})
+	assert.Equal(t, "CheckerFunc", r.Name())
+
+	resp, err := registry.CheckNow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusFail, resp.Status)
+
+	r.Deregister()
+
+	resp, err = registry.CheckNow(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusPass, resp.Status)
+	assert.Empty(t, resp.Checks)
+
+	registry.DeregisterAll()
+	r.Deregister()
+}
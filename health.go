@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 
 	sauté "gitlab.com/biffen/saute"
 
@@ -19,48 +20,7 @@ var (
 // CheckNow returns the current (local) health status accumulated from all
 // registered health checkers.
 func CheckNow(ctx context.Context) (resp Response, err error) {
-	ctx, span := sauté.TraceFunc(ctx, nil)
-	defer span.End()
-
-	checkersMu.RLock()
-	defer checkersMu.RUnlock()
-
-	span.AddEvent("lock")
-
-	var (
-		mu sync.Mutex
-		wg sync.WaitGroup
-	)
-
-	resp.Checks = make(map[string][]Check, len(checkers))
-
-	for name, checker := range checkers {
-		if checker == nil {
-			// Was deregistered
-			continue
-		}
-
-		select {
-		case <-ctx.Done():
-			err = ctx.Err()
-			return resp, err
-
-		default:
-
-			wg.Go(func() {
-				checks := checkOne(ctx, checker)
-
-				mu.Lock()
-				defer mu.Unlock()
-
-				resp.AddChecks(name, checks...)
-			})
-		}
-	}
-
-	wg.Wait()
-
-	return resp, nil
+	return defaultRegistry.CheckNow(ctx)
 }
 
 func checkOne(ctx context.Context, checker Checker) (checks []Check) {
@@ -91,17 +51,19 @@ const (
 )
 
 var (
-	checkers     map[string]Checker
-	checkersMu   sync.RWMutex
-	logSubsystem = slog.String("subsystem", "health")
+	defaultRegistry Registry
+	logSubsystem    = slog.String("subsystem", "health")
 )
 
+// DefaultRegistry returns the default [Registry], i.e. the one used by the
+// package-level functions, e.g. [Register] and [CheckNow].
+func DefaultRegistry() *Registry {
+	return &defaultRegistry
+}
+
 // DeregisterAll removes all previously registered health checkers.
 func DeregisterAll() {
-	checkersMu.Lock()
-	defer checkersMu.Unlock()
-
-	checkers = nil
+	defaultRegistry.DeregisterAll()
 }
 
 // Checker can be implemented by anything whose health can be checked.
@@ -126,18 +88,135 @@ func (f CheckerFunc) LogValue() slog.Value {
 // is being checked.
 type Registered struct {
 	string
+	registry *Registry
 }
 
 // Register registers a health checker.
 func Register(ctx context.Context, name string, checker Checker) Registered {
-	checkersMu.Lock()
-	defer checkersMu.Unlock()
+	return defaultRegistry.Register(ctx, name, checker)
+}
+
+// RegisterFunc registers a health check function.
+func RegisterFunc(
+	ctx context.Context,
+	name string,
+	f func(context.Context) []Check,
+) Registered {
+	return Register(ctx, name, CheckerFunc(f))
+}
 
-	if checkers == nil {
-		checkers = make(map[string]Checker)
+// Deregister removes a previously registered health checker.
+func (r Registered) Deregister() {
+	r.registry.mu.Lock()
+	defer r.registry.mu.Unlock()
+
+	if r.registry.checkers != nil {
+		r.registry.checkers[r.string] = nil
 	}
+}
 
-	name = internal.InsertUnique(checkers, name, checker)
+// Name returns the (unique) name the health checker was registered with.
+func (r Registered) Name() string {
+	return r.string
+}
+
+// Registry is a set of registered health checkers. The package-level functions,
+// e.g. [Register] and [CheckNow], use a default Registry, but separate ones can
+// be created for e.g. serving different sets of checks with [NewHandler].
+//
+// The zero value is an empty Registry ready to use.
+type Registry struct {
+	checkers    map[string]Checker
+	draining    atomic.Bool
+	forced      *ForcedStatus
+	maintenance map[string]Maintenance
+	mu          sync.RWMutex
+}
+
+// CheckNow returns the current health status accumulated from all health
+// checkers registered in the Registry.
+func (reg *Registry) CheckNow(ctx context.Context) (resp Response, err error) {
+	return reg.check(ctx, false)
+}
+
+// check runs the registered health checkers, or only the ones included in
+// [ProbeLiveness] (see [Liveness]).
+func (reg *Registry) check(
+	ctx context.Context,
+	liveness bool,
+) (resp Response, err error) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	span.AddEvent("lock")
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	resp.Checks = make(map[string][]Check, len(reg.checkers))
+
+	for name, checker := range reg.checkers {
+		if checker == nil {
+			// Was deregistered
+			continue
+		}
+
+		if _, ok := checker.(livenessChecker); liveness && !ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return resp, err
+
+		default:
+
+			wg.Go(func() {
+				checks := reg.applyMaintenance(name, checkOne(ctx, checker))
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				resp.AddChecks(name, checks...)
+			})
+		}
+	}
+
+	wg.Wait()
+
+	resp.Notes = append(resp.Notes, reg.maintenanceNotes()...)
+
+	return resp, nil
+}
+
+// DeregisterAll removes all health checkers from the Registry.
+func (reg *Registry) DeregisterAll() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.checkers = nil
+}
+
+// Register registers a health checker in the Registry.
+func (reg *Registry) Register(
+	ctx context.Context,
+	name string,
+	checker Checker,
+) Registered {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.checkers == nil {
+		reg.checkers = make(map[string]Checker)
+	}
+
+	name = internal.InsertUnique(reg.checkers, name, checker)
 
 	slog.DebugContext(ctx, "registered health checker",
 		logSubsystem,
@@ -145,22 +224,14 @@ func Register(ctx context.Context, name string, checker Checker) Registered {
 		slog.Any("checker", checker),
 	)
 
-	return Registered{name}
+	return Registered{name, reg}
 }
 
-// RegisterFunc registers a health check function.
-func RegisterFunc(
+// RegisterFunc registers a health check function in the Registry.
+func (reg *Registry) RegisterFunc(
 	ctx context.Context,
 	name string,
 	f func(context.Context) []Check,
 ) Registered {
-	return Register(ctx, name, CheckerFunc(f))
-}
-
-// Deregister removes a previously registered health checker.
-func (r Registered) Deregister() {
-	checkersMu.Lock()
-	defer checkersMu.Unlock()
-
-	checkers[r.string] = nil
+	return reg.Register(ctx, name, CheckerFunc(f))
 }
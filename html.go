@@ -0,0 +1,93 @@
+package health
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"time"
+)
+
+// ContentTypeHTML is the media (MIME) type of the HTML status page served to
+// browsers.
+const ContentTypeHTML = "text/html"
+
+var (
+	//go:embed html.tmpl
+	htmlSource   string
+	htmlTemplate = template.Must(template.New("html").Parse(htmlSource))
+)
+
+type htmlCheck struct {
+	Check
+	Name string
+}
+
+// Observed formats the observed value and unit of a check, turning durations
+// into something readable.
+func (c htmlCheck) Observed() string {
+	if c.ObservedValue == nil {
+		return ""
+	}
+
+	if c.ObservedUnit == "ns" {
+		if f, ok := toFloat(c.ObservedValue); ok {
+			return time.Duration(f).String()
+		}
+	}
+
+	if c.ObservedUnit == "" {
+		return fmt.Sprint(c.ObservedValue)
+	}
+
+	return fmt.Sprintf("%v %s", c.ObservedValue, c.ObservedUnit)
+}
+
+type htmlData struct {
+	Checks   []htmlCheck
+	Now      time.Time
+	Refresh  int
+	Response *Response
+}
+
+// writeHTML writes a self-contained HTML page describing a Response. If refresh
+// is positive the page will ask the browser to reload it at that interval.
+func writeHTML(w io.Writer, resp *Response, refresh time.Duration) error {
+	data := htmlData{
+		Now:      time.Now(),
+		Refresh:  int(refresh.Seconds()),
+		Response: resp,
+	}
+
+	names := make([]string, 0, len(resp.Checks))
+	for name := range resp.Checks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, check := range resp.Checks[name] {
+			data.Checks = append(data.Checks, htmlCheck{
+				Check: check,
+				Name:  name,
+			})
+		}
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,51 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestHandleHTTP_HTML(t *testing.T) {
+	ctx := context.Background()
+
+	r := health.RegisterFunc(ctx, t.Name(), func(context.Context) []health.Check {
+		check := health.Check{
+			ComponentID: "<db>",
+			Status:      health.StatusWarn,
+			Output:      "slow & steady",
+		}
+		check.SetObservedTime(1500 * time.Millisecond)
+
+		return []health.Check{check}
+	})
+	defer r.Deregister()
+
+	req := httptest.NewRequest(http.MethodGet, "/?refresh=5", nil)
+	req.Header.Set(headers.Accept, "text/html")
+
+	w := httptest.NewRecorder()
+	health.HandleHTTP(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, health.ContentTypeHTML, resp.Header.Get(headers.ContentType))
+
+	body := w.Body.String()
+	assert.Contains(t, body, t.Name())
+	assert.Contains(t, body, `<meta http-equiv="refresh" content="5">`)
+	assert.Contains(t, body, `class="status warn"`)
+	assert.Contains(t, body, "&lt;db&gt;")
+	assert.Contains(t, body, "slow &amp; steady")
+	assert.Contains(t, body, "1.5s")
+}
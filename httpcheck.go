@@ -0,0 +1,285 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ HTTPCheckOption = httpCheckOptionFunc(nil)
+
+// HTTPCheckOption is an optional configuration for [RegisterHTTP].
+type HTTPCheckOption interface {
+	applyHTTPCheck(*httpCheck)
+}
+
+// RegisterHTTP registers a checker that requests a URL and checks the
+// response. By default a GET request is made and any 2xx status code passes.
+//
+// The response time is recorded (see [Check.SetObservedTime]) and the output
+// includes the status code.
+func RegisterHTTP(
+	ctx context.Context,
+	url, name string,
+	base Check,
+	options ...HTTPCheckOption,
+) Registered {
+	if base.ComponentType == "" {
+		base.ComponentType = ComponentTypeComponent
+	}
+
+	c := httpCheck{
+		base:   base,
+		client: &http.Client{Timeout: timeout},
+		method: http.MethodGet,
+		url:    url,
+	}
+
+	for _, option := range options {
+		option.applyHTTPCheck(&c)
+	}
+
+	return Register(ctx, name, namedFunc{
+		string:      "http",
+		CheckerFunc: c.check,
+	})
+}
+
+// WithBodyContains is an [HTTPCheckOption] for [RegisterHTTP] to require the
+// response body to contain a string.
+func WithBodyContains(substr string) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			if !strings.Contains(string(body), substr) {
+				return fmt.Errorf("body doesn’t contain %q", substr)
+			}
+
+			return nil
+		})
+	})
+}
+
+// WithBodyRegexp is an [HTTPCheckOption] for [RegisterHTTP] to require the
+// response body to match a regular expression.
+func WithBodyRegexp(re *regexp.Regexp) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			if !re.Match(body) {
+				return fmt.Errorf("body doesn’t match %q", re)
+			}
+
+			return nil
+		})
+	})
+}
+
+// WithExpectedStatus is an [HTTPCheckOption] for [RegisterHTTP] to specify
+// the status codes that pass. The default is any 2xx status code.
+func WithExpectedStatus(statusCodes ...int) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.statusCodes = append(c.statusCodes, statusCodes...)
+	})
+}
+
+// WithHTTPCheckClient is an [HTTPCheckOption] for [RegisterHTTP] to use a
+// custom [net/http.Client]. The default has a timeout of 30 s.
+func WithHTTPCheckClient(client *http.Client) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.client = client
+	})
+}
+
+// WithHTTPHeader is an [HTTPCheckOption] for [RegisterHTTP] to add a header to
+// the request.
+func WithHTTPHeader(key, value string) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+
+		c.header.Add(key, value)
+	})
+}
+
+// WithHTTPMethod is an [HTTPCheckOption] for [RegisterHTTP] to specify the
+// request method. The default is GET.
+func WithHTTPMethod(method string) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.method = method
+	})
+}
+
+// WithJSONPath is an [HTTPCheckOption] for [RegisterHTTP] to require the
+// response body to be JSON with a value at a path. The path is a dot-separated
+// list of object keys and array indices, e.g. ‘data.items.0.state’. The value
+// is compared with strings as is and with other types JSON-encoded, e.g.
+// ‘true’ or ‘42’.
+func WithJSONPath(path, value string) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			var v any
+
+			if err := json.Unmarshal(body, &v); err != nil {
+				return fmt.Errorf("body isn’t JSON: %w", err)
+			}
+
+			actual, ok := jsonPath(v, path)
+			if !ok {
+				return fmt.Errorf("no %q in body", path)
+			}
+
+			if actual != value {
+				return fmt.Errorf("%q is %q, not %q", path, actual, value)
+			}
+
+			return nil
+		})
+	})
+}
+
+// WithLatency is an [HTTPCheckOption] for [RegisterHTTP] to warn or fail when
+// the response time is at least warn or fail, respectively. A threshold of 0
+// is ignored.
+func WithLatency(warn, fail time.Duration) HTTPCheckOption {
+	return httpCheckOptionFunc(func(c *httpCheck) {
+		c.warnLatency, c.failLatency = warn, fail
+	})
+}
+
+type httpCheck struct {
+	assertions  []func([]byte) error
+	base        Check
+	client      *http.Client
+	failLatency time.Duration
+	header      http.Header
+	method      string
+	statusCodes []int
+	url         string
+	warnLatency time.Duration
+}
+
+func (c *httpCheck) check(ctx context.Context) []Check {
+	check := c.base
+
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, nil)
+	if err != nil {
+		check.Status = StatusFail
+		check.Output = err.Error()
+
+		return []Check{check}
+	}
+
+	maps.Copy(req.Header, c.header.Clone())
+
+	start := time.Now()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		check.SetObservedTime(time.Since(start))
+		check.Status = StatusFail
+		check.Output = err.Error()
+
+		return []Check{check}
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	latency := time.Since(start)
+
+	check.SetObservedTime(latency)
+
+	problems := []string{resp.Status}
+
+	switch {
+	case err != nil:
+		check.Status = StatusFail
+		problems = append(problems, err.Error())
+
+	case !c.expected(resp.StatusCode):
+		check.Status = StatusFail
+		problems = append(problems, "unexpected status")
+
+	default:
+		for _, assertion := range c.assertions {
+			if err := assertion(body); err != nil {
+				check.Status = StatusFail
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	switch {
+	case c.failLatency > 0 && latency >= c.failLatency:
+		check.Status = StatusFail
+		problems = append(problems, "slow response: "+latency.String())
+
+	case c.warnLatency > 0 && latency >= c.warnLatency:
+		check.Status = WorstStatus(check.Status, StatusWarn)
+		problems = append(problems, "slow response: "+latency.String())
+	}
+
+	check.Output = strings.Join(problems, ": ")
+
+	return []Check{check}
+}
+
+func (c *httpCheck) expected(statusCode int) bool {
+	if len(c.statusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+
+	return slices.Contains(c.statusCodes, statusCode)
+}
+
+// jsonPath returns the value at a dot-separated path in decoded JSON, as a
+// string.
+func jsonPath(v any, path string) (string, bool) {
+	if path != "" {
+		for key := range strings.SplitSeq(path, ".") {
+			switch t := v.(type) {
+			case map[string]any:
+				var ok bool
+				if v, ok = t[key]; !ok {
+					return "", false
+				}
+
+			case []any:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(t) {
+					return "", false
+				}
+
+				v = t[i]
+
+			default:
+				return "", false
+			}
+		}
+	}
+
+	if str, ok := v.(string); ok {
+		return str, true
+	}
+
+	bajts, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+
+	return string(bajts), true
+}
+
+type httpCheckOptionFunc func(*httpCheck)
+
+func (f httpCheckOptionFunc) applyHTTPCheck(c *httpCheck) {
+	f(c)
+}
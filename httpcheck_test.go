@@ -0,0 +1,128 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegisterHTTP(t *testing.T) {
+	ctx := t.Context()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/slow":
+				time.Sleep(50 * time.Millisecond)
+
+			case "/teapot":
+				w.WriteHeader(http.StatusTeapot)
+				return
+			}
+
+			if req.Method != http.MethodHead &&
+				req.Header.Get("X-Test") == "yes" {
+				_, _ = w.Write([]byte(`{"data":{"items":[{"state":"up"}]}}`))
+			}
+		},
+	))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name    string
+		options []health.HTTPCheckOption
+		output  string
+		path    string
+		status  health.Status
+	}{
+		{
+			name: "default",
+			options: []health.HTTPCheckOption{
+				health.WithHTTPHeader("X-Test", "yes"),
+				health.WithBodyContains(`"state"`),
+				health.WithBodyRegexp(regexp.MustCompile(`"items":\[`)),
+				health.WithJSONPath("data.items.0.state", "up"),
+			},
+			output: "200 OK",
+			status: health.StatusPass,
+		},
+		{
+			name:    "method",
+			options: []health.HTTPCheckOption{health.WithHTTPMethod("HEAD")},
+			output:  "200 OK",
+			status:  health.StatusPass,
+		},
+		{
+			name: "body",
+			options: []health.HTTPCheckOption{
+				health.WithBodyContains("down"),
+				health.WithJSONPath("data.items.1.state", "up"),
+			},
+			output: "200 OK: body doesn’t contain \"down\": body isn’t JSON",
+			status: health.StatusFail,
+		},
+		{
+			name:   "status",
+			output: "418 I'm a teapot: unexpected status",
+			path:   "/teapot",
+			status: health.StatusFail,
+		},
+		{
+			name: "expected status",
+			options: []health.HTTPCheckOption{
+				health.WithExpectedStatus(http.StatusTeapot),
+			},
+			output: "418 I'm a teapot",
+			path:   "/teapot",
+			status: health.StatusPass,
+		},
+		{
+			name: "slow",
+			options: []health.HTTPCheckOption{
+				health.WithLatency(10*time.Millisecond, time.Minute),
+			},
+			output: "200 OK: slow response",
+			path:   "/slow",
+			status: health.StatusWarn,
+		},
+		{
+			name: "timeout",
+			options: []health.HTTPCheckOption{
+				health.WithHTTPCheckClient(&http.Client{
+					Timeout: 10 * time.Millisecond,
+				}),
+			},
+			output: "Client.Timeout exceeded",
+			path:   "/slow",
+			status: health.StatusFail,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := health.RegisterHTTP(
+				ctx,
+				server.URL+tc.path,
+				"http",
+				health.Check{},
+				tc.options...,
+			)
+			defer r.Deregister()
+
+			resp, err := health.CheckNow(ctx)
+			require.NoError(t, err)
+			require.Len(t, resp.Checks[r.Name()], 1)
+
+			check := resp.Checks[r.Name()][0]
+			assert.Equal(t, tc.status, check.Status)
+			assert.Contains(t, check.Output, tc.output)
+			assert.Equal(t, health.ComponentTypeComponent, check.ComponentType)
+			assert.Equal(t, "ns", check.ObservedUnit)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package internal
+
+// Map returns a new slice with f applied to each element of s.
+func Map[S ~[]E, E, T any](s S, f func(E) T) []T {
+	t := make([]T, 0, len(s))
+
+	for _, e := range s {
+		t = append(t, f(e))
+	}
+
+	return t
+}
@@ -0,0 +1,20 @@
+package internal_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dotse/go-health/internal"
+)
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		[]string{"1", "2", "3"},
+		internal.Map([]int{1, 2, 3}, strconv.Itoa),
+	)
+	assert.Empty(t, internal.Map([]int(nil), strconv.Itoa))
+}
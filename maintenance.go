@@ -0,0 +1,184 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ForcedStatus is a status that overrides the actual status of
+// [ProbeReadiness]; see [Registry.ForceStatus].
+type ForcedStatus struct {
+	Status Status    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+	Until  time.Time `json:"until,omitzero"`
+}
+
+// Maintenance marks a registered health checker as being in maintenance; see
+// [Registry.SetMaintenance].
+type Maintenance struct {
+	Reason string    `json:"reason,omitempty"`
+	Until  time.Time `json:"until,omitzero"`
+}
+
+// ForceStatus forces the status of [ProbeReadiness] responses, e.g. to take an
+// instance out of rotation (‘fail’) or to keep it in despite known failures
+// (‘pass’). It lasts until [Registry.UnforceStatus] is called or, if set, the
+// Until time.
+func (reg *Registry) ForceStatus(ctx context.Context, forced ForcedStatus) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.forced = &forced
+
+	slog.InfoContext(ctx, "forced health status",
+		logSubsystem,
+		slog.Any("status", forced.Status),
+		slog.String("reason", forced.Reason),
+		slog.Time("until", forced.Until),
+	)
+}
+
+// Forced returns the currently forced status, if any.
+func (reg *Registry) Forced() (ForcedStatus, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	if reg.forced == nil || expired(reg.forced.Until) {
+		return ForcedStatus{}, false
+	}
+
+	return *reg.forced, true
+}
+
+// InMaintenance returns the registered health checkers currently in
+// maintenance, by name.
+func (reg *Registry) InMaintenance() map[string]Maintenance {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	m := make(map[string]Maintenance, len(reg.maintenance))
+
+	for name, maintenance := range reg.maintenance {
+		if !expired(maintenance.Until) {
+			m[name] = maintenance
+		}
+	}
+
+	return m
+}
+
+// SetMaintenance marks a registered health checker as being in maintenance. Its
+// checks still run, but their statuses are at worst ‘warn’. It lasts until
+// [Registry.EndMaintenance] is called or, if set, the Until time.
+//
+// Returns an error if there is no health checker registered with the name.
+func (reg *Registry) SetMaintenance(
+	ctx context.Context,
+	name string,
+	maintenance Maintenance,
+) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.checkers[name] == nil {
+		return fmt.Errorf("no health checker registered as %q", name)
+	}
+
+	if reg.maintenance == nil {
+		reg.maintenance = make(map[string]Maintenance)
+	}
+
+	reg.maintenance[name] = maintenance
+
+	slog.InfoContext(ctx, "health checker in maintenance",
+		logSubsystem,
+		slog.String("name", name),
+		slog.String("reason", maintenance.Reason),
+		slog.Time("until", maintenance.Until),
+	)
+
+	return nil
+}
+
+// EndMaintenance ends the maintenance of a registered health checker.
+func (reg *Registry) EndMaintenance(ctx context.Context, name string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, ok := reg.maintenance[name]; ok {
+		delete(reg.maintenance, name)
+
+		slog.InfoContext(ctx, "health checker out of maintenance",
+			logSubsystem,
+			slog.String("name", name),
+		)
+	}
+}
+
+// UnforceStatus stops forcing the status; see [Registry.ForceStatus].
+func (reg *Registry) UnforceStatus(ctx context.Context) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.forced != nil {
+		reg.forced = nil
+
+		slog.InfoContext(ctx, "unforced health status", logSubsystem)
+	}
+}
+
+// applyMaintenance caps the statuses of checks from a health checker in
+// maintenance. The caller must hold (at least) a read lock.
+func (reg *Registry) applyMaintenance(name string, checks []Check) []Check {
+	maintenance, ok := reg.maintenance[name]
+	if !ok || expired(maintenance.Until) {
+		return checks
+	}
+
+	checks = slices.Clone(checks)
+
+	for i := range checks {
+		checks[i].Status = min(checks[i].Status, StatusWarn)
+	}
+
+	return checks
+}
+
+// maintenanceNotes returns notes about all health checkers in maintenance. The
+// caller must hold (at least) a read lock.
+func (reg *Registry) maintenanceNotes() (notes []string) {
+	for name, maintenance := range reg.maintenance {
+		if reg.checkers[name] == nil || expired(maintenance.Until) {
+			continue
+		}
+
+		notes = append(notes, note(
+			fmt.Sprintf("%s is in maintenance", name),
+			maintenance.Reason,
+			maintenance.Until,
+		))
+	}
+
+	slices.Sort(notes)
+
+	return notes
+}
+
+func expired(until time.Time) bool {
+	return !until.IsZero() && time.Now().After(until)
+}
+
+func note(str, reason string, until time.Time) string {
+	if !until.IsZero() {
+		str += " until " + until.Format(time.RFC3339)
+	}
+
+	if reason != "" {
+		str += ": " + reason
+	}
+
+	return str
+}
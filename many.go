@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sauté "gitlab.com/biffen/saute"
+)
+
+const defaultConcurrency = 10
+
+var _ CheckManyOption = checkManyOptionFunc(nil)
+
+// Result is the result of checking the health of a target.
+type Result struct {
+	// Target is the target as passed to [CheckMany]. It is empty for
+	// [Watch].
+	Target string
+	// Response is the Response, unless there’s an error.
+	Response *Response
+	// Err is the error, if any.
+	Err error
+	// Latency is the time the check took.
+	Latency time.Duration
+	// Time is when the check was started.
+	Time time.Time
+}
+
+// Check returns a Check summarising the Result.
+func (result *Result) Check() Check {
+	check := Check{
+		ComponentType: ComponentTypeComponent,
+		Status:        StatusFail,
+		Time:          &result.Time,
+	}
+
+	check.SetObservedTime(result.Latency)
+
+	switch {
+	case result.Err != nil:
+		check.Output = result.Err.Error()
+
+	case result.Response != nil:
+		check.Status = result.Response.Status
+		check.Output = result.Response.Output
+	}
+
+	return check
+}
+
+// Results are the results of [CheckMany], in the same order as the targets.
+type Results []Result
+
+// Err returns the errors of all Results joined, or nil if there were none.
+func (results Results) Err() error {
+	errs := make([]error, 0, len(results))
+
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Response aggregates the Results into a Response with one entry per target.
+func (results Results) Response() *Response {
+	var resp Response
+
+	for _, result := range results {
+		resp.AddChecks(result.Target, result.Check())
+	}
+
+	return &resp
+}
+
+// CheckMany checks the health of several targets concurrently. Each target is
+// anything [WithHost] accepts, e.g. ‘host:port’ or a URL. See
+// [WithCheckOptions] for options that apply to all targets.
+//
+// The number of concurrent checks is limited; see [WithConcurrency].
+//
+// An error is only returned for invalid options; errors for individual targets
+// are in the Results.
+func CheckMany(
+	ctx context.Context,
+	targets []string,
+	options ...CheckManyOption,
+) (Results, error) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	c := checkManyConfig{
+		Concurrency: defaultConcurrency,
+	}
+
+	for _, option := range options {
+		if err := option.applyCheckMany(&c); err != nil {
+			return nil, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+
+	if _, err := newConfig(c.Options); err != nil {
+		return nil, err
+	}
+
+	var (
+		results   = make(Results, len(targets))
+		semaphore = make(chan struct{}, c.Concurrency)
+		wg        sync.WaitGroup
+	)
+
+	for i, target := range targets {
+		wg.Go(func() {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = check(ctx, target, c.Options)
+		})
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// CheckManyOption is an optional configuration for [CheckMany].
+type CheckManyOption interface {
+	applyCheckMany(*checkManyConfig) error
+}
+
+// WithConcurrency is a [CheckManyOption] for [CheckMany] to specify the
+// maximum number of concurrent checks. The default is 10.
+func WithConcurrency(concurrency uint) CheckManyOption {
+	return checkManyOptionFunc(func(c *checkManyConfig) error {
+		if concurrency == 0 {
+			return errors.New("zero concurrency")
+		}
+
+		c.Concurrency = concurrency
+
+		return nil
+	})
+}
+
+type checkManyConfig struct {
+	Concurrency uint
+	Options     []Option
+}
+
+type checkManyOptionFunc func(*checkManyConfig) error
+
+func (f checkManyOptionFunc) applyCheckMany(c *checkManyConfig) error {
+	return f(c)
+}
+
+// check checks the health of a single target for [CheckMany].
+func check(ctx context.Context, target string, options []Option) Result {
+	result := Result{
+		Target: target,
+		Time:   time.Now(),
+	}
+
+	result.Response, result.Err = CheckHealth(
+		ctx,
+		append(options[:len(options):len(options)], WithHost(target))...,
+	)
+	result.Latency = time.Since(result.Time)
+
+	return result
+}
@@ -0,0 +1,93 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestCheckMany(t *testing.T) {
+	t.Parallel()
+
+	var current, peak atomic.Int32
+
+	serve := func(status health.Status) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				n := current.Add(1)
+				defer current.Add(-1)
+
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+
+				time.Sleep(20 * time.Millisecond)
+
+				w.Header().Set("Content-Type", health.ContentType)
+				_, _ = w.Write([]byte(`{"status":"` + status.String() + `"}`))
+			},
+		))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	var targets []string
+
+	for range 3 {
+		targets = append(targets, serve(health.StatusPass).URL)
+	}
+
+	warn := serve(health.StatusWarn).URL
+	targets = append(targets, warn, "unix:///nonexistent/health.sock")
+
+	results, err := health.CheckMany(t.Context(), targets,
+		health.WithCheckOptions(health.WithTimeout(5*time.Second)),
+		health.WithConcurrency(2),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, len(targets))
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+
+	for i, result := range results {
+		assert.Equal(t, targets[i], result.Target)
+		assert.False(t, result.Time.IsZero())
+		assert.Positive(t, result.Latency)
+	}
+
+	assert.Equal(t, health.StatusPass, results[0].Response.Status)
+	assert.Equal(t, health.StatusWarn, results[3].Response.Status)
+	require.ErrorIs(t, results.Err(), health.ErrUnreachable)
+
+	resp := results.Response()
+	assert.Equal(t, health.StatusFail, resp.Status)
+	assert.Len(t, resp.Checks, len(targets))
+	assert.Equal(t, health.StatusWarn, resp.Checks[warn][0].Status)
+	assert.NotEmpty(t, resp.Checks["unix:///nonexistent/health.sock"][0].Output)
+}
+
+func TestCheckMany_options(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	_, err := health.CheckMany(ctx, []string{"example.com"},
+		health.WithConcurrency(0),
+	)
+	assert.ErrorContains(t, err, "invalid option")
+
+	_, err = health.CheckMany(ctx, []string{"example.com"},
+		health.WithCheckOptions(health.WithURL("ftp://example.com/")),
+	)
+	assert.ErrorContains(t, err, "invalid option")
+}
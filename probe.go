@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	// ProbeReadiness is whether a service is ready to receive traffic. It
+	// fails while draining.
+	ProbeReadiness Probe = iota
+	// ProbeLiveness is whether a service is alive, i.e. shouldn’t be
+	// restarted. It is unaffected by draining and only includes the health
+	// checkers that opt in (see [Liveness]).
+	ProbeLiveness
+)
+
+// OutputDraining is the output of responses for [ProbeReadiness] while
+// draining.
+const OutputDraining = "draining"
+
+var (
+	_ Checker        = livenessChecker{}
+	_ slog.LogValuer = livenessChecker{}
+)
+
+// Probe is a kind of health check, with slightly different semantics.
+type Probe uint8
+
+// BeginShutdown sets the default registry to draining (see [SetDraining]) and
+// then waits for delay, e.g. so that load balancers have time to notice before
+// the service stops accepting traffic. Returns early with an error if the
+// passed [context.Context] is cancelled.
+func BeginShutdown(ctx context.Context, delay time.Duration) error {
+	SetDraining(ctx, true)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Liveness wraps a [Checker] to include it in [ProbeLiveness], which otherwise
+// runs no checks. Only wrap checks of the service itself, e.g. for deadlocks,
+// as failing liveness gets the service restarted; a failing dependency, e.g. a
+// database, should only fail [ProbeReadiness].
+func Liveness(checker Checker) Checker {
+	return livenessChecker{checker}
+}
+
+// Draining returns true if the default registry is draining.
+func Draining() bool {
+	return defaultRegistry.Draining()
+}
+
+// SetDraining sets whether the default registry is draining. See
+// [Registry.SetDraining].
+func SetDraining(ctx context.Context, draining bool) {
+	defaultRegistry.SetDraining(ctx, draining)
+}
+
+// ApplyProbe derives the Response for a [Probe] from a Response from
+// [Registry.CheckNow], without running any checks again. See
+// [Registry.CheckProbe].
+func (reg *Registry) ApplyProbe(resp Response, probe Probe) Response {
+	switch probe {
+	case ProbeReadiness:
+		if forced, ok := reg.Forced(); ok {
+			resp.Status = forced.Status
+			resp.Notes = append(slices.Clip(resp.Notes), note(
+				"status forced to "+forced.Status.String(),
+				forced.Reason,
+				forced.Until,
+			))
+		}
+
+		if reg.Draining() {
+			resp.Status = StatusFail
+			resp.Output = OutputDraining
+		}
+
+	case ProbeLiveness:
+		checks := resp.Checks
+
+		resp.Checks = make(map[string][]Check, len(checks))
+		resp.Status = StatusPass
+
+		reg.mu.RLock()
+		defer reg.mu.RUnlock()
+
+		for name, checks := range checks {
+			if _, ok := reg.checkers[name].(livenessChecker); ok {
+				resp.AddChecks(name, checks...)
+			}
+		}
+	}
+
+	return resp
+}
+
+// CheckProbe is like [Registry.CheckNow] but for a particular [Probe]. I.e. for
+// [ProbeReadiness] a forced status (see [Registry.ForceStatus]) applies and the
+// status is ‘fail’ while draining, and [ProbeLiveness] only runs the health
+// checkers wrapped with [Liveness].
+func (reg *Registry) CheckProbe(
+	ctx context.Context,
+	probe Probe,
+) (resp Response, err error) {
+	resp, err = reg.check(ctx, probe == ProbeLiveness)
+	if err != nil {
+		return resp, err
+	}
+
+	return reg.ApplyProbe(resp, probe), nil
+}
+
+// Draining returns true if the Registry is draining.
+func (reg *Registry) Draining() bool {
+	return reg.draining.Load()
+}
+
+// SetDraining sets whether the Registry is draining. While draining responses
+// for [ProbeReadiness] fail (with the output [OutputDraining]) while
+// [ProbeLiveness] is unaffected.
+func (reg *Registry) SetDraining(ctx context.Context, draining bool) {
+	if reg.draining.Swap(draining) != draining {
+		slog.InfoContext(ctx, "health draining changed",
+			logSubsystem,
+			slog.Bool("draining", draining),
+		)
+	}
+}
+
+// String returns ‘readiness’ or ‘liveness’.
+func (probe Probe) String() string {
+	switch probe {
+	case ProbeReadiness:
+		return "readiness"
+
+	case ProbeLiveness:
+		return "liveness"
+
+	default:
+		return ""
+	}
+}
+
+type livenessChecker struct {
+	Checker
+}
+
+func (c livenessChecker) LogValue() slog.Value {
+	if valuer, ok := c.Checker.(slog.LogValuer); ok {
+		return valuer.LogValue()
+	}
+
+	return slog.AnyValue(c.Checker)
+}
@@ -0,0 +1,112 @@
+package health_test
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegistry_SetDraining(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusPass}}
+	})
+
+	handler := health.NewHandler(health.WithRegistry(&registry))
+
+	get := func(path string) int {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return w.Code
+	}
+
+	assert.False(t, registry.Draining())
+	assert.Equal(t, http.StatusOK, get("/"))
+	assert.Equal(t, http.StatusOK, get("/livez"))
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+
+	registry.SetDraining(ctx, true)
+
+	assert.True(t, registry.Draining())
+	assert.Equal(t, http.StatusInternalServerError, get("/"))
+	assert.Equal(t, http.StatusOK, get("/livez"))
+	assert.Equal(t, http.StatusInternalServerError, get("/readyz"))
+
+	resp, err := registry.CheckProbe(ctx, health.ProbeReadiness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusFail, resp.Status)
+	assert.Equal(t, health.OutputDraining, resp.Output)
+
+	resp, err = registry.CheckProbe(ctx, health.ProbeLiveness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusPass, resp.Status)
+
+	registry.SetDraining(ctx, false)
+
+	assert.Equal(t, http.StatusOK, get("/readyz"))
+}
+
+func TestLiveness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		registry health.Registry
+	)
+
+	registry.RegisterFunc(ctx, "db", func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusFail}}
+	})
+
+	self := registry.Register(ctx, "self", health.Liveness(health.CheckerFunc(
+		func(context.Context) []health.Check {
+			return []health.Check{{Status: health.StatusWarn}}
+		},
+	)))
+
+	resp, err := registry.CheckProbe(ctx, health.ProbeLiveness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+	assert.Equal(t, []string{self.Name()}, slices.Collect(maps.Keys(resp.Checks)))
+
+	resp, err = registry.CheckProbe(ctx, health.ProbeReadiness)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusFail, resp.Status)
+	assert.Len(t, resp.Checks, 2)
+
+	resp = registry.ApplyProbe(resp, health.ProbeLiveness)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+	assert.Equal(t, []string{self.Name()}, slices.Collect(maps.Keys(resp.Checks)))
+}
+
+func TestBeginShutdown(t *testing.T) {
+	ctx := context.Background()
+
+	defer health.SetDraining(ctx, false)
+
+	require.NoError(t, health.BeginShutdown(ctx, time.Millisecond))
+	assert.True(t, health.Draining())
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.ErrorIs(t, health.BeginShutdown(ctx, time.Hour), context.Canceled)
+}
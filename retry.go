@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Retryable reports whether an error returned by [CheckHealth] is likely
+// transient, i.e.:
+//
+//   - the connection is refused (or the Unix domain socket doesn’t exist yet)
+//   - a timeout
+//   - a [*StatusError] with 502 Bad Gateway or 503 Service Unavailable
+//
+// This is the default for [WithRetryIf].
+func Retryable(err error) bool {
+	if statusErr := (*StatusError)(nil); errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusBadGateway ||
+			statusErr.StatusCode == http.StatusServiceUnavailable
+	}
+
+	if !errors.Is(err, ErrUnreachable) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENOENT) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// WithBackoff is an [Option] for [CheckHealth] to specify the delay before the
+// first retry, and the maximum delay. The delay is doubled for each retry,
+// with some random jitter. The default is 100 ms, up to 5 s.
+func WithBackoff(initial, maximum time.Duration) Option {
+	return optionFunc(func(c *config) error {
+		if initial <= 0 || maximum < initial {
+			return fmt.Errorf("invalid backoff %s–%s", initial, maximum)
+		}
+
+		c.Backoff, c.MaxBackoff = initial, maximum
+
+		return nil
+	})
+}
+
+// WithRetries is an [Option] for [CheckHealth] to retry up to a number of
+// times when a check fails with a retryable error (see [WithRetryIf]). Retries
+// never extend beyond the timeout (see [WithTimeout]) or the deadline of the
+// passed [context.Context]. The default is not to retry.
+func WithRetries(retries uint) Option {
+	return optionFunc(func(c *config) error {
+		c.Retries = retries
+		return nil
+	})
+}
+
+// WithRetryIf is an [Option] for [CheckHealth] to specify which errors to
+// retry. The default is [Retryable].
+func WithRetryIf(retryable func(error) bool) Option {
+	return optionFunc(func(c *config) error {
+		if retryable == nil {
+			return errors.New("nil retry function")
+		}
+
+		c.RetryIf = retryable
+
+		return nil
+	})
+}
+
+// wait sleeps before a retry. It returns false, without sleeping, if the
+// context would be done before the retry.
+func (c *config) wait(ctx context.Context, attempt uint) bool {
+	delay := backoff(c.Backoff, c.MaxBackoff, attempt)
+
+	// ‘Equal jitter’: somewhere between half and all of the delay.
+	delay = delay/2 + rand.N(delay/2+1)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+
+	case <-timer.C:
+		return true
+	}
+}
+
+// backoff returns the initial delay doubled once per attempt, but never more
+// than the maximum. The check is done before each doubling so that large
+// delays can’t overflow.
+func backoff(initial, maximum time.Duration, attempt uint) time.Duration {
+	delay := initial
+
+	for range attempt {
+		if delay > maximum/2 {
+			return maximum
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, maximum)
+}
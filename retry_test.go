@@ -0,0 +1,157 @@
+package health_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{&health.StatusError{StatusCode: http.StatusBadGateway}, true},
+		{&health.StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&health.StatusError{StatusCode: http.StatusNotFound}, false},
+		{&health.ContentTypeError{ContentType: "text/html"}, false},
+		{&health.DecodeError{Err: errors.New("EOF")}, false},
+		{errors.New("something else"), false},
+	} {
+		assert.Equal(t, tc.retryable, health.Retryable(tc.err), tc.err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		initial, maximum time.Duration
+		attempt          uint
+		expected         time.Duration
+	}{
+		{time.Millisecond, time.Second, 0, time.Millisecond},
+		{time.Millisecond, time.Second, 3, 8 * time.Millisecond},
+		{time.Millisecond, time.Second, 10, time.Second},
+		{time.Millisecond, time.Second, 1000, time.Second},
+		{time.Hour, 2 * time.Hour, 40, 2 * time.Hour},
+		{time.Hour, 2 * time.Hour, 1 << 20, 2 * time.Hour},
+		{time.Nanosecond, 1<<63 - 1, 70, 1<<63 - 1},
+	} {
+		assert.Equal(
+			t,
+			tc.expected,
+			health.Backoff(tc.initial, tc.maximum, tc.attempt),
+			"%s–%s, attempt %d", tc.initial, tc.maximum, tc.attempt,
+		)
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		var requests atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				if requests.Add(1) <= 2 {
+					http.Error(w, "starting", http.StatusServiceUnavailable)
+					return
+				}
+
+				w.Header().Set("Content-Type", health.ContentType)
+				_, _ = w.Write([]byte(`{"status":"pass"}`))
+			},
+		))
+		defer server.Close()
+
+		// No retries by default.
+		_, err := health.CheckHealth(ctx, health.WithURL(server.URL))
+
+		var statusErr *health.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+		assert.Equal(t, int32(1), requests.Load())
+
+		requests.Store(0)
+
+		resp, err := health.CheckHealth(ctx,
+			health.WithBackoff(time.Millisecond, 10*time.Millisecond),
+			health.WithRetries(3),
+			health.WithURL(server.URL),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, health.StatusPass, resp.Status)
+		assert.EqualValues(t, 3, requests.Load())
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		t.Parallel()
+
+		var requests atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, req *http.Request) {
+				requests.Add(1)
+				http.NotFound(w, req)
+			},
+		))
+		defer server.Close()
+
+		_, err := health.CheckHealth(ctx,
+			health.WithBackoff(time.Millisecond, 10*time.Millisecond),
+			health.WithRetries(3),
+			health.WithURL(server.URL),
+		)
+		require.Error(t, err)
+		assert.EqualValues(t, 1, requests.Load())
+
+		requests.Store(0)
+
+		_, err = health.CheckHealth(ctx,
+			health.WithBackoff(time.Millisecond, 10*time.Millisecond),
+			health.WithRetries(3),
+			health.WithRetryIf(func(error) bool { return true }),
+			health.WithURL(server.URL),
+		)
+		require.Error(t, err)
+		assert.EqualValues(t, 4, requests.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		start := time.Now()
+
+		_, err = health.CheckHealth(ctx,
+			health.WithBackoff(10*time.Millisecond, 50*time.Millisecond),
+			health.WithHost(addr),
+			health.WithRetries(1000),
+			health.WithTimeout(300*time.Millisecond),
+		)
+		require.ErrorIs(t, err, health.ErrUnreachable)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,280 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHeapFail = 95
+	defaultHeapWarn = 80
+
+	metricGCCPU        = "/cpu/classes/gc/total:cpu-seconds"
+	metricGCPauses     = "/sched/pauses/total/gc:seconds"
+	metricGoroutines   = "/sched/goroutines:goroutines"
+	metricHeapReleased = "/memory/classes/heap/released:bytes"
+	metricMemLimit     = "/gc/gomemlimit:bytes"
+	metricMemTotal     = "/memory/classes/total:bytes"
+	metricTotalCPU     = "/cpu/classes/total:cpu-seconds"
+)
+
+var _ RuntimeCheckOption = runtimeCheckOptionFunc(nil)
+
+// RuntimeCheckOption is an optional configuration for [RegisterRuntime].
+type RuntimeCheckOption interface {
+	applyRuntimeCheck(*runtimeCheck)
+}
+
+// RegisterRuntime registers a checker for the Go runtime, based on
+// [runtime/metrics]. There are Checks, with component IDs:
+//
+//   - ‘goroutines’: the number of goroutines
+//   - ‘heap’: the memory in use in percent of the memory limit (see
+//     [runtime/debug.SetMemoryLimit]), or in bytes if there is no limit. Like
+//     the limit, this is all memory mapped by the Go runtime (not only heap
+//     objects) minus what has been released to the operating system.
+//   - ‘gc-cpu’: the CPU time spent on garbage collection, in percent, since
+//     the previous check
+//   - ‘gc-pause’: the longest garbage collection pause since the previous
+//     check
+//
+// Each Check warns or fails above thresholds. By default only the heap has
+// thresholds: warn above 80 % and fail above 95 %.
+func RegisterRuntime(
+	ctx context.Context,
+	name string,
+	base Check,
+	options ...RuntimeCheckOption,
+) Registered {
+	if base.ComponentType == "" {
+		base.ComponentType = ComponentTypeSystem
+	}
+
+	c := runtimeCheck{
+		base:     base,
+		heapFail: defaultHeapFail,
+		heapWarn: defaultHeapWarn,
+	}
+
+	for _, option := range options {
+		option.applyRuntimeCheck(&c)
+	}
+
+	return Register(ctx, name, namedFunc{
+		string:      "runtime",
+		CheckerFunc: c.check,
+	})
+}
+
+// WithGCCPULimits is a [RuntimeCheckOption] for [RegisterRuntime] to warn or
+// fail when the CPU time spent on garbage collection is above percentages. A
+// threshold of 0 is ignored.
+func WithGCCPULimits(warn, fail float64) RuntimeCheckOption {
+	return runtimeCheckOptionFunc(func(c *runtimeCheck) {
+		c.gcCPUWarn, c.gcCPUFail = warn, fail
+	})
+}
+
+// WithGCPauseLimits is a [RuntimeCheckOption] for [RegisterRuntime] to warn or
+// fail when a garbage collection pause is longer than durations. A threshold
+// of 0 is ignored.
+func WithGCPauseLimits(warn, fail time.Duration) RuntimeCheckOption {
+	return runtimeCheckOptionFunc(func(c *runtimeCheck) {
+		c.gcPauseWarn, c.gcPauseFail = warn, fail
+	})
+}
+
+// WithGoroutineLimits is a [RuntimeCheckOption] for [RegisterRuntime] to warn
+// or fail when the number of goroutines is above limits. A threshold of 0 is
+// ignored.
+func WithGoroutineLimits(warn, fail uint64) RuntimeCheckOption {
+	return runtimeCheckOptionFunc(func(c *runtimeCheck) {
+		c.goroutinesWarn, c.goroutinesFail = warn, fail
+	})
+}
+
+// WithHeapLimits is a [RuntimeCheckOption] for [RegisterRuntime] to warn or
+// fail when the memory in use (see [RegisterRuntime]) is above percentages of
+// the memory limit. A threshold of 0 is ignored.
+func WithHeapLimits(warn, fail float64) RuntimeCheckOption {
+	return runtimeCheckOptionFunc(func(c *runtimeCheck) {
+		c.heapWarn, c.heapFail = warn, fail
+	})
+}
+
+type runtimeCheck struct {
+	base           Check
+	gcCPUFail      float64
+	gcCPUWarn      float64
+	gcPauseFail    time.Duration
+	gcPauseWarn    time.Duration
+	goroutinesFail uint64
+	goroutinesWarn uint64
+	heapFail       float64
+	heapWarn       float64
+
+	// The cumulative metrics from the previous check.
+	mu                 sync.Mutex
+	prevGCCPU, prevCPU float64
+	prevPauses         []uint64
+}
+
+func (c *runtimeCheck) check(context.Context) []Check {
+	samples := []metrics.Sample{
+		{Name: metricGCCPU},
+		{Name: metricGCPauses},
+		{Name: metricGoroutines},
+		{Name: metricHeapReleased},
+		{Name: metricMemLimit},
+		{Name: metricMemTotal},
+		{Name: metricTotalCPU},
+	}
+
+	metrics.Read(samples)
+
+	values := make(map[string]metrics.Value, len(samples))
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return []Check{
+		c.goroutines(values[metricGoroutines].Uint64()),
+		c.heap(
+			values[metricMemTotal].Uint64()-
+				values[metricHeapReleased].Uint64(),
+			values[metricMemLimit].Uint64(),
+		),
+		c.gcCPU(
+			values[metricGCCPU].Float64(),
+			values[metricTotalCPU].Float64(),
+		),
+		c.gcPause(values[metricGCPauses].Float64Histogram()),
+	}
+}
+
+func (c *runtimeCheck) gcCPU(gcCPU, totalCPU float64) Check {
+	check := c.base
+	check.ComponentID = "gc-cpu"
+	check.ObservedUnit = "%"
+
+	var percent float64
+	if total := totalCPU - c.prevCPU; total > 0 {
+		percent = math.Round((gcCPU-c.prevGCCPU)/total*10000) / 100
+	}
+
+	c.prevGCCPU, c.prevCPU = gcCPU, totalCPU
+
+	check.ObservedValue = percent
+	check.Status = WorstStatus(
+		check.Status,
+		exceeds(percent, c.gcCPUWarn, c.gcCPUFail),
+	)
+
+	return check
+}
+
+func (c *runtimeCheck) gcPause(pauses *metrics.Float64Histogram) Check {
+	check := c.base
+	check.ComponentID = "gc-pause"
+
+	var longest time.Duration
+
+	// Find the highest bucket with new pauses.
+	for i, count := range slices.Backward(pauses.Counts) {
+		var previous uint64
+		if i < len(c.prevPauses) {
+			previous = c.prevPauses[i]
+		}
+
+		if count <= previous {
+			continue
+		}
+
+		seconds := pauses.Buckets[i+1]
+		if math.IsInf(seconds, 1) {
+			seconds = pauses.Buckets[i]
+		}
+
+		longest = time.Duration(seconds * float64(time.Second))
+
+		break
+	}
+
+	c.prevPauses = slices.Clone(pauses.Counts)
+
+	check.SetObservedTime(longest)
+	check.Status = WorstStatus(check.Status, exceeds(
+		float64(longest),
+		float64(c.gcPauseWarn),
+		float64(c.gcPauseFail),
+	))
+
+	return check
+}
+
+func (c *runtimeCheck) goroutines(goroutines uint64) Check {
+	check := c.base
+	check.ComponentID = "goroutines"
+	check.ObservedValue = goroutines
+	check.ObservedUnit = "goroutines"
+	check.Status = WorstStatus(check.Status, exceeds(
+		float64(goroutines),
+		float64(c.goroutinesWarn),
+		float64(c.goroutinesFail),
+	))
+
+	return check
+}
+
+func (c *runtimeCheck) heap(heap, limit uint64) Check {
+	check := c.base
+	check.ComponentID = "heap"
+
+	if limit == 0 || limit >= math.MaxInt64 {
+		check.ObservedValue = heap
+		check.ObservedUnit = "B"
+
+		return check
+	}
+
+	percent := percentage(heap, limit)
+
+	check.ObservedValue = percent
+	check.ObservedUnit = "%"
+	check.Output = fmt.Sprintf("%d of %d bytes", heap, limit)
+	check.Status = WorstStatus(
+		check.Status,
+		exceeds(percent, c.heapWarn, c.heapFail),
+	)
+
+	return check
+}
+
+// exceeds returns the status for a value given warn and fail thresholds
+// (above which the status is ‘warn’ or ‘fail’, respectively).
+func exceeds(value, warn, fail float64) Status {
+	switch {
+	case fail > 0 && value > fail:
+		return StatusFail
+
+	case warn > 0 && value > warn:
+		return StatusWarn
+
+	default:
+		return StatusPass
+	}
+}
+
+type runtimeCheckOptionFunc func(*runtimeCheck)
+
+func (f runtimeCheckOptionFunc) applyRuntimeCheck(c *runtimeCheck) {
+	f(c)
+}
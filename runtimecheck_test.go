@@ -0,0 +1,69 @@
+package health_test
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestRegisterRuntime(t *testing.T) {
+	ctx := t.Context()
+
+	checkNow := func(r health.Registered) map[string]health.Check {
+		t.Helper()
+
+		resp, err := health.CheckNow(ctx)
+		require.NoError(t, err)
+
+		checks := make(map[string]health.Check)
+		for _, check := range resp.Checks[r.Name()] {
+			assert.Equal(t, health.ComponentTypeSystem, check.ComponentType)
+			checks[check.ComponentID] = check
+		}
+
+		require.Len(t, checks, 4)
+
+		return checks
+	}
+
+	r := health.RegisterRuntime(ctx, "runtime", health.Check{})
+
+	runtime.GC()
+
+	checks := checkNow(r)
+	assert.Equal(t, "goroutines", checks["goroutines"].ObservedUnit)
+	assert.Positive(t, checks["goroutines"].ObservedValue)
+	assert.Equal(t, "%", checks["gc-cpu"].ObservedUnit)
+	assert.Equal(t, "ns", checks["gc-pause"].ObservedUnit)
+
+	for _, check := range checks {
+		assert.Equal(t, health.StatusPass, check.Status, check.ComponentID)
+	}
+
+	r.Deregister()
+
+	r = health.RegisterRuntime(ctx, "runtime", health.Check{},
+		health.WithGCPauseLimits(0, time.Nanosecond),
+		health.WithGoroutineLimits(1, 0),
+		health.WithHeapLimits(0.01, 0),
+	)
+	defer r.Deregister()
+
+	limit := debug.SetMemoryLimit(1 << 28)
+	defer debug.SetMemoryLimit(limit)
+
+	checkNow(r)
+	runtime.GC()
+
+	checks = checkNow(r)
+	assert.Equal(t, health.StatusWarn, checks["goroutines"].Status)
+	assert.Equal(t, health.StatusWarn, checks["heap"].Status)
+	assert.Equal(t, "%", checks["heap"].ObservedUnit)
+	assert.Equal(t, health.StatusFail, checks["gc-pause"].Status)
+}
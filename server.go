@@ -2,21 +2,20 @@ package health
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/netip"
 	"os"
+	"os/signal"
 	"strconv"
-	"strings"
 	"sync"
+	"syscall"
 	"time"
-
-	"github.com/go-http-utils/headers"
-	"github.com/go-http-utils/negotiator"
-	sauté "gitlab.com/biffen/saute"
-	"go.opentelemetry.io/otel/codes"
 )
 
 const (
@@ -25,139 +24,264 @@ const (
 	// EnvHealthPort is ‘HEALTH_PORT’; the environment variable to configure the
 	// health check HTTP port.
 	EnvHealthPort = "HEALTH_PORT"
+	// EnvHealthSocket is ‘HEALTH_SOCKET’; the environment variable to configure
+	// a Unix domain socket path to use instead of TCP.
+	EnvHealthSocket = "HEALTH_SOCKET"
+
+	defaultSocketMode = 0o660
 )
 
 var (
-	server   *http.Server
-	serverMu sync.Mutex
+	_ ServerOption = serverOptionFunc(nil)
+
+	server    *http.Server
+	serverCtx context.Context
+	serverMu  sync.Mutex
+
+	// Whether a stopping server has set the default registry to draining.
+	serverDraining bool
 )
 
-// HandleHTTP serves a health response over HTTP. It supports the GET, HEAD and
-// OPTIONS methods as well as content negotiation.
-func HandleHTTP(w http.ResponseWriter, req *http.Request) {
-	ctx, span := sauté.TraceFunc(req.Context(), nil)
-	defer span.End()
+// StartServer starts an HTTP server at 0.0.0.0:${HEALTH_PORT:-9999} serving
+// health checks. Can be called multiple times but will only start one server.
+//
+// If ${HEALTH_SOCKET} is set, or [WithServerSocket] is used, the server listens
+// on that Unix domain socket instead.
+//
+// The server uses the default configuration of [NewHandler], i.e. it serves
+// responses at ‘/’, ‘/livez’ and ‘/readyz’ and Server-Sent Events at
+// ‘/events’. Use [WithHandlerOptions] to change that, e.g. to restrict access
+// with [WithAllowlist].
+//
+// Will block until the server is listening.
+//
+// The server will be stopped when the passed [context.Context] is cancelled.
+// See [WithShutdownDelay] for stopping gracefully.
+//
+// Options only take effect when a server is actually started.
+func StartServer(ctx context.Context, options ...ServerOption) error {
+	var c serverConfig
 
-	errorStatus := func(err error, status int) {
-		if err == nil {
-			http.Error(w, "", status)
-			span.SetStatus(codes.Error, http.StatusText(status))
+	for _, option := range options {
+		if err := option.applyServer(&c); err != nil {
+			return fmt.Errorf("invalid option: %w", err)
+		}
+	}
 
-			return
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	if server != nil {
+		if serverCtx.Err() == nil {
+			return nil
 		}
 
-		http.Error(w, err.Error(), status)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		// The previous server is on its way down; make sure it has let go of
+		// the port before starting a new one.
+		_ = server.Close()
+		server = nil
 	}
 
-	switch req.Method {
-	case http.MethodOptions:
-		w.Header().Set(headers.Allow, strings.Join([]string{
-			http.MethodGet,
-			http.MethodHead,
-			http.MethodOptions,
-		}, ", "))
-		w.WriteHeader(http.StatusNoContent)
+	if serverDraining {
+		SetDraining(ctx, false)
 
-		return
+		serverDraining = false
+	}
 
-	case http.MethodGet, http.MethodHead:
+	if c.SocketPath == "" {
+		c.SocketPath = os.Getenv(EnvHealthSocket)
+	}
 
-	default:
-		errorStatus(nil, http.StatusMethodNotAllowed)
-		return
+	var (
+		addr     string
+		listener net.Listener
+		err      error
+	)
+
+	if c.SocketPath == "" {
+		addr = netip.AddrPortFrom(netip.IPv4Unspecified(), port()).String()
+		listener, err = net.Listen("tcp", addr)
+	} else {
+		addr = c.SocketPath
+		listener, err = listenUnix(c.SocketPath, c.SocketMode)
 	}
 
-	negotiator := negotiator.New(req.Header)
+	if err != nil {
+		return err
+	}
 
-	if negotiator.Charset("UTF-8") == "" {
-		http.Error(w, "", http.StatusNotAcceptable)
-		return
+	if c.TLSConfig != nil {
+		listener = tls.NewListener(listener, c.TLSConfig)
 	}
 
-	ct := negotiator.Type(ContentType, "application/json")
-	if ct == "" {
-		errorStatus(nil, http.StatusNotAcceptable)
-		return
+	var handler http.Handler = defaultHandler
+	if c.HandlerOptions != nil {
+		handler = NewHandler(c.HandlerOptions...)
 	}
 
-	w.Header().Set(headers.ContentEncoding, "UTF-8")
-	w.Header().Set(headers.ContentType, ct)
+	// Long-lived requests, i.e. event streams, are cancelled on shutdown.
+	baseCtx, cancelBase := context.WithCancel(context.WithoutCancel(ctx))
 
-	resp, err := CheckNow(ctx)
-	if err != nil {
-		errorStatus(err, http.StatusInternalServerError)
-		return
+	s := &http.Server{
+		Addr:              addr,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+		Handler:           handler,
+		TLSConfig:         c.TLSConfig,
+		ReadHeaderTimeout: 30 * time.Second,
 	}
 
-	if resp.Status == StatusFail {
-		w.WriteHeader(http.StatusInternalServerError)
-	}
+	s.RegisterOnShutdown(cancelBase)
+
+	server, serverCtx = s, ctx
 
-	if req.Method == http.MethodHead {
-		w.Header().Set(headers.ContentLength, "0")
+	go func() {
+		if err := s.Serve(listener); err != nil &&
+			!errors.Is(err, http.ErrServerClosed) {
+			slog.ErrorContext(ctx, "health server error",
+				slog.Any("error", err),
+			)
+		}
+
+		serverMu.Lock()
+		defer serverMu.Unlock()
+
+		if server == s {
+			server = nil
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+
+		if c.ShutdownDelay > 0 {
+			drain(ctx, s, c.ShutdownDelay)
+		}
+
+		if err := s.Shutdown(context.Background()); err != nil {
+			slog.ErrorContext(ctx, "error when stopping health server",
+				slog.Any("error", err),
+			)
+		}
+	}()
+
+	return nil
+}
+
+// drain sets the default registry to draining and waits for delay, or until
+// the process gets another interrupt or termination signal. Does nothing if
+// another server has already been started.
+func drain(ctx context.Context, s *http.Server, delay time.Duration) {
+	serverMu.Lock()
+
+	if server != s {
+		serverMu.Unlock()
 		return
 	}
 
-	if _, err := resp.Write(w); err != nil {
-		errorStatus(err, http.StatusInternalServerError)
+	serverDraining = true
+
+	serverMu.Unlock()
+
+	SetDraining(ctx, true)
+
+	signalled, stop := signal.NotifyContext(
+		context.WithoutCancel(ctx),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-signalled.Done():
+	case <-timer.C:
 	}
 }
 
-// StartServer starts an HTTP server at 0.0.0.0:${HEALTH_PORT:-9999} serving
-// health checks. Can be called multiple times but will only start one server.
-//
-// Will block until the server is listening.
+// ServerOption is an optional configuration for [StartServer].
+type ServerOption interface {
+	applyServer(*serverConfig) error
+}
+
+// WithShutdownDelay is a [ServerOption] for [StartServer] to make the server
+// start draining (see [SetDraining]) when its [context.Context] is cancelled,
+// and then wait for delay before actually stopping. This gives e.g. load
+// balancers time to notice that the service is going away.
 //
-// The server will be stopped when the passed [context.Context] is cancelled.
-func StartServer(ctx context.Context) error {
-	serverMu.Lock()
-	defer serverMu.Unlock()
+// Another interrupt or termination signal (e.g. a second Ctrl+C) during the
+// delay stops the server at once. Draining ends when a new server is started.
+func WithShutdownDelay(delay time.Duration) ServerOption {
+	return serverOptionFunc(func(c *serverConfig) error {
+		if delay < 0 {
+			return fmt.Errorf("negative shutdown delay: %s", delay)
+		}
 
-	if server == nil {
-		addr := netip.AddrPortFrom(netip.IPv4Unspecified(), port())
+		c.ShutdownDelay = delay
 
-		listener, err := net.Listen("tcp", addr.String())
-		if err != nil {
-			return err
-		}
+		return nil
+	})
+}
 
-		mux := http.NewServeMux()
-		mux.HandleFunc("GET /{$}", HandleHTTP)
+// WithHandlerOptions is a [ServerOption] for [StartServer] to configure the
+// server’s handler; see [NewHandler].
+func WithHandlerOptions(options ...HandlerOption) ServerOption {
+	return serverOptionFunc(func(c *serverConfig) error {
+		c.HandlerOptions = append(c.HandlerOptions, options...)
+		return nil
+	})
+}
 
-		server = &http.Server{
-			Addr:              addr.String(),
-			Handler:           mux,
-			ReadHeaderTimeout: 30 * time.Second,
+// WithServerSocket is a [ServerOption] for [StartServer] to listen on a Unix
+// domain socket, with the given file mode, instead of TCP. Overrides
+// ${HEALTH_SOCKET}.
+func WithServerSocket(path string, mode fs.FileMode) ServerOption {
+	return serverOptionFunc(func(c *serverConfig) error {
+		if path == "" {
+			return errors.New("empty socket path")
 		}
 
-		go func() {
-			if err := server.Serve(listener); err != nil &&
-				!errors.Is(err, http.ErrServerClosed) {
-				slog.ErrorContext(ctx, "health server error",
-					slog.Any("error", err),
-				)
-			}
+		c.SocketPath, c.SocketMode = path, mode
 
-			serverMu.Lock()
-			defer serverMu.Unlock()
+		return nil
+	})
+}
 
-			server = nil
-		}()
+// WithServerTLS is a [ServerOption] for [StartServer] to serve HTTPS. Set
+// [crypto/tls.Config.ClientAuth] for mutual TLS, e.g. to use
+// [ClientCertificate] with [WithAdmin].
+func WithServerTLS(config *tls.Config) ServerOption {
+	return serverOptionFunc(func(c *serverConfig) error {
+		c.TLSConfig = config
+		return nil
+	})
+}
 
-		go func() {
-			<-ctx.Done()
+// listenUnix listens on a Unix domain socket, replacing any stale socket file.
+func listenUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	if mode == 0 {
+		mode = defaultSocketMode
+	}
 
-			if err := server.Shutdown(context.Background()); err != nil {
-				slog.ErrorContext(ctx, "error when stopping health server",
-					slog.Any("error", err),
-				)
-			}
-		}()
+	if info, err := os.Stat(path); err == nil &&
+		info.Mode().Type() == fs.ModeSocket {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
 }
 
 func port() uint16 {
@@ -175,3 +299,17 @@ func port() uint16 {
 
 	return 9_999
 }
+
+type serverConfig struct {
+	HandlerOptions []HandlerOption
+	ShutdownDelay  time.Duration
+	SocketMode     fs.FileMode
+	SocketPath     string
+	TLSConfig      *tls.Config
+}
+
+type serverOptionFunc func(*serverConfig) error
+
+func (f serverOptionFunc) applyServer(c *serverConfig) error {
+	return f(c)
+}
@@ -2,12 +2,17 @@ package health_test
 
 import (
 	"context"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-http-utils/headers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dotse/go-health"
 )
@@ -74,7 +79,7 @@ func TestHandle(t *testing.T) {
 	test(
 		"bad type",
 		http.MethodGet,
-		map[string]string{headers.Accept: "text/html"},
+		map[string]string{headers.Accept: "image/png"},
 		nil,
 		http.StatusNotAcceptable,
 	)
@@ -102,7 +107,7 @@ func TestHandle(t *testing.T) {
 		"good charset, bad type",
 		http.MethodGet,
 		map[string]string{
-			headers.Accept:        "text/html",
+			headers.Accept:        "image/png",
 			headers.AcceptCharset: "UTF-8",
 		},
 		nil,
@@ -149,6 +154,14 @@ func TestHandle(t *testing.T) {
 		http.StatusOK,
 	)
 
+	test(
+		"HTML",
+		http.MethodGet,
+		map[string]string{headers.Accept: "text/html,*/*;q=0.8"},
+		map[string]string{headers.ContentType: health.ContentTypeHTML},
+		http.StatusOK,
+	)
+
 	test(
 		"good charset, good type",
 		http.MethodGet,
@@ -169,3 +182,59 @@ func TestHandle(t *testing.T) {
 		http.StatusInternalServerError,
 	)
 }
+
+func TestStartServer_socket(t *testing.T) {
+	var (
+		ctx    = t.Context()
+		socket = filepath.Join(t.TempDir(), "health.sock")
+	)
+
+	r := health.RegisterFunc(ctx, t.Name(), func(context.Context) []health.Check {
+		return []health.Check{{Status: health.StatusWarn}}
+	})
+	defer r.Deregister()
+
+	require.NoError(t, health.StartServer(ctx,
+		health.WithHandlerOptions(health.WithAllowlist(health.DetailStatus)),
+		health.WithServerSocket(socket, 0o600),
+	))
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, fs.ModeSocket|0o600, info.Mode())
+
+	resp, err := health.CheckHealth(ctx, health.WithHost("unix://"+socket))
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+	assert.Contains(t, resp.Checks, r.Name())
+
+	t.Setenv(health.EnvHealthSocket, socket)
+
+	resp, err = health.CheckHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusWarn, resp.Status)
+}
+
+func TestStartServer_restart(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "health.sock")
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	require.NoError(t, health.StartServer(ctx,
+		health.WithServerSocket(socket, 0o600),
+		health.WithShutdownDelay(10*time.Millisecond),
+	))
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, err := health.CheckHealth(t.Context(), health.WithHost("unix://"+socket))
+		return health.Draining() && err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, health.StartServer(t.Context(),
+		health.WithServerSocket(socket, 0o600),
+	))
+
+	assert.False(t, health.Draining())
+}
@@ -0,0 +1,206 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	sauté "gitlab.com/biffen/saute"
+)
+
+const (
+	// EnvNotifySocket is ‘NOTIFY_SOCKET’; the environment variable systemd
+	// uses to tell services where to send notifications.
+	EnvNotifySocket = "NOTIFY_SOCKET"
+	// EnvWatchdogPID is ‘WATCHDOG_PID’; the environment variable systemd uses
+	// to tell which process the watchdog is for.
+	EnvWatchdogPID = "WATCHDOG_PID"
+	// EnvWatchdogUSec is ‘WATCHDOG_USEC’; the environment variable systemd uses
+	// to tell the watchdog timeout in microseconds.
+	EnvWatchdogUSec = "WATCHDOG_USEC"
+
+	systemdInterval = 5 * time.Second
+)
+
+// StartSystemdNotify starts notifying systemd (see sd_notify(3)) about the
+// health of the default registry:
+//
+//   - ‘READY=1’ as soon as [ProbeReadiness] first passes
+//   - ‘STATUS=…’ summarising failing checks, whenever it changes
+//   - ‘WATCHDOG=1’ at half the watchdog interval, but only while
+//     [ProbeLiveness] passes, so that systemd restarts a wedged service
+//   - ‘STOPPING=1’ when the passed [context.Context] is cancelled
+//
+// Does nothing if ${NOTIFY_SOCKET} is not set, i.e. when not run by systemd
+// with ‘Type=notify’.
+func StartSystemdNotify(ctx context.Context) error {
+	path := os.Getenv(EnvNotifySocket)
+	if path == "" {
+		return nil
+	}
+
+	watchdog, err := watchdogInterval()
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", EnvNotifySocket, err)
+	}
+
+	interval := systemdInterval
+	if watchdog > 0 {
+		interval = min(interval, watchdog/2)
+	}
+
+	go func() {
+		defer conn.Close()
+
+		n := systemdNotifier{
+			conn:     conn,
+			registry: &defaultRegistry,
+			watchdog: watchdog > 0,
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			n.notify(ctx)
+
+			select {
+			case <-ctx.Done():
+				_ = n.send(context.WithoutCancel(ctx), "STOPPING=1")
+				return
+
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+type systemdNotifier struct {
+	conn     net.Conn
+	ready    bool
+	registry *Registry
+	status   string
+	watchdog bool
+}
+
+func (n *systemdNotifier) notify(ctx context.Context) {
+	ctx, span := sauté.TraceFunc(ctx, nil)
+	defer span.End()
+
+	resp, err := n.registry.CheckNow(ctx)
+	if err != nil {
+		return
+	}
+
+	var (
+		lines     []string
+		readiness = n.registry.ApplyProbe(resp, ProbeReadiness)
+	)
+
+	if !n.ready && readiness.Good() {
+		n.ready = true
+
+		lines = append(lines, "READY=1")
+	}
+
+	if status := systemdStatus(&readiness); status != n.status {
+		n.status = status
+
+		lines = append(lines, "STATUS="+status)
+	}
+
+	if n.watchdog {
+		if liveness := n.registry.ApplyProbe(
+			resp,
+			ProbeLiveness,
+		); liveness.Good() {
+			lines = append(lines, "WATCHDOG=1")
+		}
+	}
+
+	if len(lines) > 0 {
+		_ = n.send(ctx, lines...)
+	}
+}
+
+func (n *systemdNotifier) send(ctx context.Context, lines ...string) error {
+	_, err := n.conn.Write([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to notify systemd",
+			logSubsystem,
+			slog.Any("error", err),
+		)
+	}
+
+	return err
+}
+
+// systemdStatus summarises a Response on a single line, e.g. ‘fail: db,
+// cache’.
+func systemdStatus(resp *Response) string {
+	var failing []string
+
+	for name, checks := range resp.Checks {
+		if slices.ContainsFunc(checks, func(check Check) bool {
+			return check.Status != StatusPass
+		}) {
+			failing = append(failing, name)
+		}
+	}
+
+	slices.Sort(failing)
+
+	str := resp.Status.String()
+
+	if resp.Output != "" {
+		str += " (" + oneLine(resp.Output) + ")"
+	}
+
+	if len(failing) > 0 {
+		str += ": " + strings.Join(failing, ", ")
+	}
+
+	return str
+}
+
+// watchdogInterval returns the systemd watchdog timeout, or 0 if there is no
+// watchdog for this process.
+func watchdogInterval() (time.Duration, error) {
+	str := os.Getenv(EnvWatchdogUSec)
+	if str == "" {
+		return 0, nil
+	}
+
+	if pid := os.Getenv(EnvWatchdogPID); pid != "" &&
+		pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+
+	usec, err := strconv.ParseUint(str, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", EnvWatchdogUSec, err)
+	}
+
+	if usec == 0 {
+		return 0, errors.New(EnvWatchdogUSec + " is 0")
+	}
+
+	return time.Duration(usec) * time.Microsecond, nil
+}
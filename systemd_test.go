@@ -0,0 +1,78 @@
+package health_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestStartSystemdNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	})
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	t.Setenv(health.EnvNotifySocket, path)
+	t.Setenv(health.EnvWatchdogUSec, "100000")
+
+	var (
+		ctx, cancel = context.WithCancel(t.Context())
+		status      atomic.Uint32
+	)
+
+	defer cancel()
+
+	status.Store(uint32(health.StatusFail))
+
+	r := health.Register(ctx, t.Name(), health.Liveness(health.CheckerFunc(
+		func(context.Context) []health.Check {
+			return []health.Check{{Status: health.Status(status.Load())}}
+		},
+	)))
+	defer r.Deregister()
+
+	require.NoError(t, health.StartSystemdNotify(ctx))
+
+	next := func() []string {
+		t.Helper()
+
+		buf := make([]byte, 1024)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	// Not ready and not alive
+	assert.Equal(t, []string{"STATUS=fail: " + r.Name()}, next())
+
+	status.Store(uint32(health.StatusPass))
+
+	assert.Equal(t, []string{"READY=1", "STATUS=pass", "WATCHDOG=1"}, next())
+	assert.Equal(t, []string{"WATCHDOG=1"}, next())
+
+	cancel()
+
+	for {
+		if lines := next(); lines[0] == "STOPPING=1" {
+			break
+		}
+	}
+}
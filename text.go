@@ -0,0 +1,85 @@
+package health
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ContentTypeText is the media (MIME) type of the plain text, Kubernetes-style,
+// verbose health report.
+const ContentTypeText = "text/plain"
+
+// writeText writes a Response in the style of Kubernetes’ ‘/healthz?verbose’,
+// i.e. one line per check followed by the overall status:
+//
+//	[+]db ok
+//	[-]cache failed: connection refused
+//	health check failed
+func writeText(w io.Writer, resp *Response) error {
+	bw := bufio.NewWriter(w)
+
+	names := make([]string, 0, len(resp.Checks))
+	for name := range resp.Checks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, check := range resp.Checks[name] {
+			label := name
+			if check.ComponentID != "" {
+				label += "/" + check.ComponentID
+			}
+
+			sign := '+'
+			if !check.Good() {
+				sign = '-'
+			}
+
+			_, _ = bw.WriteString("[" + string(sign) + "]" + label + " ")
+
+			switch check.Status {
+			case StatusPass:
+				_, _ = bw.WriteString("ok")
+
+			case StatusWarn:
+				_, _ = bw.WriteString("warn")
+
+			default:
+				_, _ = bw.WriteString("failed")
+			}
+
+			if check.Output != "" {
+				_, _ = bw.WriteString(": " + oneLine(check.Output))
+			}
+
+			_ = bw.WriteByte('\n')
+		}
+	}
+
+	switch resp.Status {
+	case StatusPass:
+		_, _ = bw.WriteString("health check passed")
+
+	case StatusWarn:
+		_, _ = bw.WriteString("health check passed with warnings")
+
+	default:
+		_, _ = bw.WriteString("health check failed")
+	}
+
+	if resp.Output != "" {
+		_, _ = bw.WriteString(": " + oneLine(resp.Output))
+	}
+
+	_ = bw.WriteByte('\n')
+
+	return bw.Flush()
+}
+
+func oneLine(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
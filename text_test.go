@@ -0,0 +1,64 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dotse/go-health"
+)
+
+func TestHandleHTTP_Text(t *testing.T) {
+	ctx := context.Background()
+
+	r := health.RegisterFunc(ctx, t.Name(), func(context.Context) []health.Check {
+		return []health.Check{
+			{Status: health.StatusPass},
+			{
+				ComponentID: "replica",
+				Status:      health.StatusFail,
+				Output:      "connection\nrefused",
+			},
+		}
+	})
+	defer r.Deregister()
+
+	check := r.Name()
+
+	for name, setup := range map[string]func(*http.Request){
+		"accept": func(req *http.Request) {
+			req.Header.Set(headers.Accept, health.ContentTypeText)
+		},
+		"verbose": func(req *http.Request) {
+			req.URL.RawQuery = "verbose"
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			setup(req)
+
+			w := httptest.NewRecorder()
+			health.HandleHTTP(w, req)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Equal(t, health.ContentTypeText, w.Header().Get(headers.ContentType))
+			assert.Equal(t, "[+]"+check+" ok\n"+
+				"[-]"+check+"/replica failed: connection refused\n"+
+				"health check failed\n", w.Body.String())
+		})
+	}
+
+	t.Run("not acceptable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?verbose", nil)
+		req.Header.Set(headers.Accept, "image/png")
+
+		w := httptest.NewRecorder()
+		health.HandleHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+}
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+var _ WatchOption = watchOptionFunc(nil)
+
+// Watch checks the health repeatedly, waiting interval between checks, until
+// the passed [context.Context] is cancelled or the caller stops iterating.
+// Each check is made like [CheckHealth] with the options from
+// [WithCheckOptions], reusing connections. An invalid option yields a single
+// Result with an error.
+//
+// See [WithOnlyChanges] for only getting the Results that differ from the
+// previous one.
+func Watch(
+	ctx context.Context,
+	interval time.Duration,
+	options ...WatchOption,
+) iter.Seq[Result] {
+	return func(yield func(Result) bool) {
+		var w watchConfig
+
+		for _, option := range options {
+			if err := option.applyWatch(&w); err != nil {
+				yield(Result{
+					Err:  fmt.Errorf("invalid option: %w", err),
+					Time: time.Now(),
+				})
+
+				return
+			}
+		}
+
+		c, err := newConfig(w.Options)
+		if err != nil {
+			yield(Result{Err: err, Time: time.Now()})
+			return
+		}
+
+		// The same client for all checks, so that connections are reused.
+		client, closeIdle, err := c.client()
+		if err != nil {
+			yield(Result{Err: err, Time: time.Now()})
+			return
+		}
+
+		defer closeIdle()
+
+		var previous *Result
+
+		for {
+			result := Result{Time: time.Now()}
+
+			result.Response, result.Err = c.checkHealth(ctx, client)
+			result.Latency = time.Since(result.Time)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !w.OnlyChanges || previous == nil ||
+				resultChanged(previous, &result) {
+				if !yield(result) {
+					return
+				}
+			}
+
+			previous = &result
+
+			timer := time.NewTimer(interval)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// WatchOption is an optional configuration for [Watch].
+type WatchOption interface {
+	applyWatch(*watchConfig) error
+}
+
+// WithOnlyChanges is a [WatchOption] for [Watch] to only yield Results whose
+// status (or error) differs from the previous one’s, including the status of
+// any check.
+func WithOnlyChanges() WatchOption {
+	return watchOptionFunc(func(c *watchConfig) error {
+		c.OnlyChanges = true
+		return nil
+	})
+}
+
+func resultChanged(old, result *Result) bool {
+	if old.Err != nil || result.Err != nil {
+		return old.Err == nil || result.Err == nil ||
+			old.Err.Error() != result.Err.Error()
+	}
+
+	return changed(old.Response, result.Response)
+}
+
+type watchConfig struct {
+	OnlyChanges bool
+	Options     []Option
+}
+
+type watchOptionFunc func(*watchConfig) error
+
+func (f watchOptionFunc) applyWatch(c *watchConfig) error {
+	return f(c)
+}
@@ -0,0 +1,137 @@
+package health_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dotse/go-health"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		checks   atomic.Int32
+		ctx      = t.Context()
+		registry health.Registry
+	)
+
+	// pass, pass, pass, warn, warn, warn, fail, fail, fail, …
+	registry.RegisterFunc(ctx, "", func(context.Context) []health.Check {
+		n := checks.Add(1) - 1
+
+		return []health.Check{{
+			Status: []health.Status{
+				health.StatusPass,
+				health.StatusWarn,
+				health.StatusFail,
+			}[min(n/3, 2)],
+		}}
+	})
+
+	server := httptest.NewServer(health.NewHandler(
+		health.WithRegistry(&registry),
+	))
+	defer server.Close()
+
+	var statuses []health.Status
+
+	for result := range health.Watch(ctx, time.Millisecond,
+		health.WithOnlyChanges(),
+		health.WithCheckOptions(health.WithURL(server.URL)),
+	) {
+		require.NoError(t, result.Err)
+		assert.False(t, result.Time.IsZero())
+		assert.Positive(t, result.Latency)
+
+		statuses = append(statuses, result.Response.Status)
+
+		if result.Response.Status == health.StatusFail {
+			break
+		}
+	}
+
+	assert.Equal(t, []health.Status{
+		health.StatusPass,
+		health.StatusWarn,
+		health.StatusFail,
+	}, statuses)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var n int
+
+	for range health.Watch(cancelled, time.Millisecond,
+		health.WithCheckOptions(health.WithURL(server.URL)),
+	) {
+		if n++; n == 2 {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, 2, n, "stops when the context is cancelled")
+}
+
+func TestWatch_connections(t *testing.T) {
+	t.Parallel()
+
+	var (
+		conns    atomic.Int32
+		ctx      = t.Context()
+		registry health.Registry
+	)
+
+	server := httptest.NewUnstartedServer(health.NewHandler(
+		health.WithRegistry(&registry),
+	))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	var n int
+
+	for result := range health.Watch(ctx, time.Millisecond,
+		health.WithCheckOptions(
+			health.WithTLSConfig(&tls.Config{RootCAs: roots}),
+			health.WithURL(server.URL),
+		),
+	) {
+		require.NoError(t, result.Err)
+
+		if n++; n == 5 {
+			break
+		}
+	}
+
+	assert.Equal(t, int32(1), conns.Load(), "reuses the connection")
+
+	var results []health.Result
+
+	for result := range health.Watch(ctx, time.Millisecond,
+		health.WithCheckOptions(health.WithURL("ftp://example.com/")),
+	) {
+		results = append(results, result)
+	}
+
+	require.Len(t, results, 1, "stops after an invalid option")
+	assert.ErrorContains(t, results[0].Err, "invalid option")
+}